@@ -6,8 +6,10 @@ package apperr
 
 import (
 	"errors"
+	"fmt"
+	"time"
 
-	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -25,6 +27,82 @@ type Err interface {
 	GRPCError(domain string) error
 }
 
+// The following interfaces are implemented by the errors created by this package.
+// They are not part of Err so that the implementations of Err outside this package keep satisfying it,
+// check them with a type assertion (ex. e.(apperr.Detailer)).
+
+// Detailer is an error that has the error details.
+type Detailer interface {
+	Details() Details
+}
+
+// Retrier is an error that tells whether the client should retry.
+type Retrier interface {
+	Retryable() bool
+	RetryDelay() time.Duration
+}
+
+// StackTracer is an error that has the stack trace.
+type StackTracer interface {
+	StackTrace() StackTrace
+}
+
+// Originator is an error that knows the services where it comes from.
+type Originator interface {
+	Domain() string
+	Upstream() []string
+}
+
+// ToGRPCError returns gRPC error of the error with the options.
+// The options are ignored for the error that is not created by this package.
+func ToGRPCError(e Err, domain string, opts ...GRPCOption) error {
+	if c, ok := e.(grpcConverter); ok {
+		return c.grpcError(domain, opts)
+	}
+	return e.GRPCError(domain)
+}
+
+type grpcConverter interface {
+	grpcError(domain string, opts []GRPCOption) error
+}
+
+var (
+	_ interface {
+		Err
+		Detailer
+		Retrier
+		StackTracer
+		Originator
+	} = (*clientError)(nil)
+	_ interface {
+		Err
+		Detailer
+		Retrier
+		StackTracer
+		Originator
+	} = (*serverError)(nil)
+)
+
+// detailsOf returns the details of the error, or the zero value if the error has no details.
+func detailsOf(e Err) Details {
+	if d, ok := e.(Detailer); ok {
+		return d.Details()
+	}
+	return Details{}
+}
+
+// domainOf returns the domain of the error, or empty if unknown.
+func domainOf(e Err) string {
+	if o, ok := e.(Originator); ok {
+		return o.Domain()
+	}
+	return ""
+}
+
+// NoDetailCode is the placeholder of the detail code for the error that has no detail code
+// (ex. the error converted from echo.HTTPError).
+const NoDetailCode = "-"
+
 // Extract is a function to extract apperr.Err from an error.
 func Extract(err error) (Err, bool) {
 	var e Err
@@ -34,31 +112,72 @@ func Extract(err error) (Err, bool) {
 	return nil, false
 }
 
+// Errors returned by ParseGRPCError.
+var (
+	ErrNotGRPCStatus = errors.New("error is not a gRPC status")
+	ErrOKStatus      = errors.New("gRPC status is OK")
+	ErrUnclassified  = errors.New("gRPC code is not classified")
+)
+
 // ExtractFromGRPCError is a function to extract apperr.Err from a gRPC error.
-func ExtractFromGRPCError(err error) (Err, bool) {
+// The error details attached by GRPCError are also restored regardless of their order.
+// It returns false for the errors that ParseGRPCError returns an error.
+func ExtractFromGRPCError(err error, opts ...ExtractOption) (Err, bool) {
+	e, perr := ParseGRPCError(err, opts...)
+	if perr != nil {
+		return nil, false
+	}
+	return e, true
+}
+
+// ParseGRPCError is like ExtractFromGRPCError but returns the reason why apperr.Err cannot be extracted.
+// It returns ErrOKStatus for nil or OK status, ErrNotGRPCStatus (wrapping `err`) for the error
+// that is not a gRPC status, and ErrUnclassified for the code that the classifier does not classify.
+func ParseGRPCError(err error, opts ...ExtractOption) (Err, error) {
+	o := extractOptions{classifier: DefaultClassifier}
+	for _, opt := range opts {
+		opt(&o)
+	}
 
 	sts, ok := status.FromError(err)
 	if !ok {
-		return nil, false
+		return nil, fmt.Errorf("%w: %w", ErrNotGRPCStatus, err)
+	}
+	if sts.Code() == codes.OK {
+		return nil, ErrOKStatus
 	}
 
-	var detailCode string
-	d := sts.Details()
-	if len(d) > 0 {
-		if e, ok := d[0].(*errdetails.ErrorInfo); ok {
-			detailCode = e.GetReason()
+	detailCode, domain, details := detailsFromProto(sts.Details())
+	upstream := parseUpstream(domain, &details)
+
+	var items []Err
+	for _, d := range sts.Details() {
+		if p, ok := d.(*spb.Status); ok {
+			if item, err := ParseGRPCError(status.FromProto(p).Err(), opts...); err == nil {
+				items = append(items, item)
+			}
 		}
 	}
 
-	switch sts.Code() {
-	case codes.PermissionDenied, codes.Unauthenticated,
-		codes.InvalidArgument, codes.NotFound, codes.AlreadyExists, codes.FailedPrecondition,
-		codes.Canceled, codes.ResourceExhausted, codes.Aborted, codes.OutOfRange:
-		return NewClientError(sts.Code(), detailCode, sts.Message()), true
-	case codes.Internal, codes.Unavailable, codes.Unimplemented,
-		codes.DeadlineExceeded, codes.DataLoss, codes.Unknown:
-		return NewServerError(sts.Code(), detailCode, sts.Message(), "grpc error is a server error"), true
-	default: // codes.OK
-		return nil, false
+	typ := o.classifier(sts.Code())
+	if len(items) > 0 && (typ == ClientError || typ == ServerError) {
+		return &multiError{
+			baseError: newBaseError(nil, sts.Code(), detailCode, sts.Message(), []Option{
+				detailsOption(details), originOption(domain, upstream),
+			}),
+			typ:  typ,
+			errs: items,
+		}, nil
+	}
+
+	switch typ {
+	case ClientError:
+		return NewClientError(sts.Code(), detailCode, sts.Message(),
+			detailsOption(details), originOption(domain, upstream)), nil
+	case ServerError:
+		return NewServerError(sts.Code(), detailCode, sts.Message(), "grpc error is a server error",
+			detailsOption(details), originOption(domain, upstream)), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnclassified, sts.Code())
 	}
 }
@@ -1,13 +1,18 @@
 package apperr_test
 
 import (
+	"database/sql"
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/takuoki/golib/apperr"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
 )
 
 func TestErr(t *testing.T) {
@@ -33,6 +38,225 @@ func TestErr(t *testing.T) {
 	})
 }
 
+func TestWrap(t *testing.T) {
+	t.Run("client", func(t *testing.T) {
+		cause := fmt.Errorf("failed to find user: %w", sql.ErrNoRows)
+		err := apperr.WrapClientError(cause, codes.NotFound, "code", "message")
+		assert.Equal(t, "message", err.Error(), "Error is not equal.")
+		assert.Equal(t, "", err.Log(), "Log is not equal.")
+		assert.Equal(t, cause, errors.Unwrap(err), "Unwrap is not equal.")
+		assert.True(t, errors.Is(err, sql.ErrNoRows), "errors.Is must find the cause.")
+	})
+	t.Run("server", func(t *testing.T) {
+		cause := fmt.Errorf("failed to query: %w", sql.ErrConnDone)
+		err := apperr.WrapServerError(cause, codes.Internal, "code", "message", "")
+		assert.Equal(t, "message", err.Error(), "Error is not equal.")
+		assert.Equal(t, "failed to query: sql: connection is already closed", err.Log(), "Log is not equal.")
+		assert.Equal(t, apperr.ServerError, err.Type(), "Type is not equal.")
+		assert.True(t, errors.Is(err, sql.ErrConnDone), "errors.Is must find the cause.")
+	})
+	t.Run("server-with-log", func(t *testing.T) {
+		err := apperr.WrapServerError(sql.ErrConnDone, codes.Internal, "code", "message", "log")
+		assert.Equal(t, "log", err.Log(), "Log is not equal.")
+	})
+	t.Run("nil-cause", func(t *testing.T) {
+		err := apperr.WrapServerError(nil, codes.Internal, "code", "message", "")
+		assert.Equal(t, "", err.Log(), "Log is not equal.")
+		assert.Nil(t, errors.Unwrap(err), "Unwrap is not nil.")
+	})
+}
+
+func TestDetails(t *testing.T) {
+	want := apperr.Details{
+		FieldViolations: []apperr.FieldViolation{
+			{Field: "name", Reason: "REQUIRED", Description: "name is required"},
+			{Field: "age", Reason: "RANGE", Description: "age must be positive"},
+		},
+		Metadata:               map[string]string{"user_id": "u1"},
+		RetryDelay:             3 * time.Second,
+		QuotaViolations:        []apperr.QuotaViolation{{Subject: "user:u1", Description: "daily limit exceeded"}},
+		PreconditionViolations: []apperr.PreconditionViolation{{Type: "TOS", Subject: "user:u1", Description: "terms not accepted"}},
+		LocalizedMessage:       &apperr.LocalizedMessage{Locale: "ja-JP", Message: "入力が不正です"},
+		HelpLinks:              []apperr.HelpLink{{Description: "docs", URL: "https://example.com/errors/E0001"}},
+	}
+	opts := []apperr.Option{
+		apperr.FieldViolationOption("name", "REQUIRED", "name is required"),
+		apperr.FieldViolationOption("age", "RANGE", "age must be positive"),
+		apperr.MetadataOption(map[string]string{"user_id": "u1"}),
+		apperr.RetryDelayOption(3 * time.Second),
+		apperr.QuotaViolationOption("user:u1", "daily limit exceeded"),
+		apperr.PreconditionViolationOption("TOS", "user:u1", "terms not accepted"),
+		apperr.LocalizedMessageOption("ja-JP", "入力が不正です"),
+		apperr.HelpLinkOption("docs", "https://example.com/errors/E0001"),
+	}
+
+	t.Run("client", func(t *testing.T) {
+		err := apperr.NewClientError(codes.InvalidArgument, "E0001", "invalid argument", opts...)
+		assert.Equal(t, want, err.(apperr.Detailer).Details(), "Details is not equal.")
+	})
+	t.Run("server", func(t *testing.T) {
+		err := apperr.NewServerError(codes.Unavailable, "S0001", "unavailable", "log", opts...)
+		assert.Equal(t, want, err.(apperr.Detailer).Details(), "Details is not equal.")
+	})
+	t.Run("no-details", func(t *testing.T) {
+		err := apperr.NewClientError(codes.InvalidArgument, "E0001", "invalid argument")
+		assert.Equal(t, apperr.Details{}, err.(apperr.Detailer).Details(), "Details is not empty.")
+	})
+	t.Run("copy", func(t *testing.T) {
+		err := apperr.NewClientError(codes.InvalidArgument, "E0001", "invalid argument", opts...)
+		d := err.(apperr.Detailer).Details()
+		d.FieldViolations[0].Field = "changed"
+		d.Metadata["user_id"] = "changed"
+		assert.Equal(t, want, err.(apperr.Detailer).Details(), "Details must not be changed.")
+	})
+	t.Run("grpc-round-trip", func(t *testing.T) {
+		err := apperr.NewClientError(codes.InvalidArgument, "E0001", "invalid argument", opts...)
+		result, ok := apperr.ExtractFromGRPCError(err.GRPCError("domain"))
+		if assert.True(t, ok, "Ok is not true.") {
+			assert.Equal(t, "E0001", result.DetailCode(), "DetailCode is not equal.")
+			assert.Equal(t, want, result.(apperr.Detailer).Details(), "Details is not equal.")
+		}
+	})
+	t.Run("grpc-any-order", func(t *testing.T) {
+		st, err := status.New(codes.Unavailable, "unavailable").WithDetails(
+			&errdetails.RetryInfo{RetryDelay: durationpb.New(time.Second)},
+			&errdetails.LocalizedMessage{Locale: "en-US", Message: "try again later"},
+			&errdetails.ErrorInfo{Reason: "S0001", Domain: "domain"},
+		)
+		if !assert.NoError(t, err) {
+			return
+		}
+		result, ok := apperr.ExtractFromGRPCError(st.Err())
+		if assert.True(t, ok, "Ok is not true.") {
+			assert.Equal(t, "S0001", result.DetailCode(), "DetailCode is not equal.")
+			assert.Equal(t, apperr.Details{
+				RetryDelay:       time.Second,
+				LocalizedMessage: &apperr.LocalizedMessage{Locale: "en-US", Message: "try again later"},
+			}, result.(apperr.Detailer).Details(), "Details is not equal.")
+		}
+	})
+}
+
+func TestRetry(t *testing.T) {
+	testcases := map[string]struct {
+		err           apperr.Err
+		wantRetryable bool
+		wantDelay     time.Duration
+	}{
+		"invalid-argument":   {err: apperr.NewClientError(codes.InvalidArgument, "code", "message")},
+		"internal":           {err: apperr.NewServerError(codes.Internal, "code", "message", "log")},
+		"unavailable":        {err: apperr.NewServerError(codes.Unavailable, "code", "message", "log"), wantRetryable: true},
+		"aborted":            {err: apperr.NewClientError(codes.Aborted, "code", "message"), wantRetryable: true},
+		"resource-exhausted": {err: apperr.NewClientError(codes.ResourceExhausted, "code", "message", apperr.RetryDelayOption(time.Minute)), wantRetryable: true, wantDelay: time.Minute},
+		"delay":              {err: apperr.NewServerError(codes.Internal, "code", "message", "log", apperr.RetryDelayOption(time.Second)), wantRetryable: true, wantDelay: time.Second},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.wantRetryable, tc.err.(apperr.Retrier).Retryable(), "Retryable is not equal.")
+			assert.Equal(t, tc.wantDelay, tc.err.(apperr.Retrier).RetryDelay(), "RetryDelay is not equal.")
+
+			result, ok := apperr.ExtractFromGRPCError(tc.err.GRPCError("domain"))
+			if assert.True(t, ok, "Ok is not true.") {
+				assert.Equal(t, tc.wantRetryable, result.(apperr.Retrier).Retryable(), "Retryable of gRPC error is not equal.")
+				assert.Equal(t, tc.wantDelay, result.(apperr.Retrier).RetryDelay(), "RetryDelay of gRPC error is not equal.")
+			}
+		})
+	}
+}
+
+func TestIs(t *testing.T) {
+	notFound := apperr.NewClientError(codes.NotFound, "E0001", "not found")
+
+	testcases := map[string]struct {
+		err  error
+		want bool
+	}{
+		"same":                {err: notFound, want: true},
+		"same-code":           {err: apperr.NewClientError(codes.NotFound, "E0001", "another message"), want: true},
+		"wrapped":             {err: fmt.Errorf("wrapped: %w", notFound), want: true},
+		"grpc":                {err: notFound.GRPCError("domain"), want: false},
+		"different-code":      {err: apperr.NewClientError(codes.InvalidArgument, "E0001", "not found")},
+		"different-detail":    {err: apperr.NewClientError(codes.NotFound, "E0002", "not found")},
+		"server":              {err: apperr.NewServerError(codes.NotFound, "E0001", "not found", "log"), want: true},
+		"cause":               {err: apperr.WrapServerError(notFound, codes.Internal, "S0001", "internal", ""), want: true},
+		"not-apperr":          {err: errors.New("not found")},
+		"nil":                 {err: nil},
+		"sql-cause-unrelated": {err: apperr.WrapClientError(sql.ErrNoRows, codes.InvalidArgument, "E0002", "invalid")},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.want, errors.Is(tc.err, notFound))
+		})
+	}
+
+	t.Run("extracted-from-grpc", func(t *testing.T) {
+		e, ok := apperr.ExtractFromGRPCError(notFound.GRPCError("domain"))
+		if assert.True(t, ok) {
+			assert.True(t, errors.Is(e, notFound), "The extracted error must match the original error.")
+		}
+	})
+	t.Run("no-detail-code", func(t *testing.T) {
+		testcases := map[string]struct {
+			err    error
+			target error
+		}{
+			"empty": {
+				err:    apperr.NewServerError(codes.Internal, "", "db down", "log"),
+				target: apperr.NewServerError(codes.Internal, "", "cache down", "log"),
+			},
+			"placeholder": {
+				err:    apperr.NewClientError(codes.NotFound, apperr.NoDetailCode, "Not Found"),
+				target: apperr.NewClientError(codes.NotFound, apperr.NoDetailCode, "Not Found"),
+			},
+		}
+
+		for name, tc := range testcases {
+			t.Run(name, func(t *testing.T) {
+				assert.False(t, errors.Is(tc.err, tc.target), "The errors without the detail code must not match.")
+			})
+		}
+	})
+	t.Run("cause", func(t *testing.T) {
+		err := apperr.WrapClientError(sql.ErrNoRows, codes.NotFound, "E0001", "not found")
+		assert.True(t, errors.Is(err, sql.ErrNoRows), "The cause must still match.")
+	})
+}
+
+// customErr is an implementation of apperr.Err outside the package.
+type customErr struct{}
+
+func (customErr) Error() string          { return "custom" }
+func (customErr) Code() codes.Code       { return codes.NotFound }
+func (customErr) DetailCode() string     { return "C0001" }
+func (customErr) Message() string        { return "custom" }
+func (customErr) Log() string            { return "" }
+func (customErr) Type() apperr.Type      { return apperr.ClientError }
+func (customErr) HTTPStatus() int        { return 404 }
+func (customErr) GRPCError(string) error { return status.Error(codes.NotFound, "custom") }
+
+func TestToGRPCError(t *testing.T) {
+	t.Run("apperr", func(t *testing.T) {
+		e := apperr.NewClientError(codes.NotFound, "E0001", "not found")
+		st, ok := status.FromError(apperr.ToGRPCError(e, "domain", apperr.UpstreamChainOption()))
+		if assert.True(t, ok) {
+			assert.Equal(t, codes.NotFound, st.Code(), "Code is not equal.")
+			assert.Equal(t, "not found", st.Message(), "Message is not equal.")
+		}
+	})
+	t.Run("custom", func(t *testing.T) {
+		var e apperr.Err = customErr{}
+		st, ok := status.FromError(apperr.ToGRPCError(e, "domain", apperr.UpstreamChainOption()))
+		if assert.True(t, ok) {
+			assert.Equal(t, codes.NotFound, st.Code(), "Code is not equal.")
+			assert.Equal(t, "custom", st.Message(), "Message is not equal.")
+		}
+		_, ok = e.(apperr.Detailer)
+		assert.False(t, ok, "The custom error must not be required to implement the optional interfaces.")
+	})
+}
+
 func TestExtract(t *testing.T) {
 	t.Run("exist", func(t *testing.T) {
 		err := apperr.NewServerError(1, "code", "message", "log")
@@ -44,6 +268,13 @@ func TestExtract(t *testing.T) {
 		}
 		assert.True(t, ok, "Ok is not true.")
 	})
+	t.Run("wrapped-cause", func(t *testing.T) {
+		cause := apperr.NewClientError(codes.NotFound, "cause", "cause")
+		err := apperr.WrapServerError(cause, codes.Internal, "code", "message", "")
+		result, ok := apperr.Extract(fmt.Errorf("wrapped: %w", err))
+		assert.True(t, ok, "Ok is not true.")
+		assert.Equal(t, err, result, "The outermost error must be extracted.")
+	})
 	t.Run("not-exist", func(t *testing.T) {
 		err := errors.New("error")
 		result, ok := apperr.Extract(err)
@@ -0,0 +1,177 @@
+package apperr
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// baseError holds the items common to client and server errors.
+type baseError struct {
+	code       codes.Code
+	detailCode string
+	message    string
+	cause      error
+	details    Details
+	stack      StackTrace
+
+	// domain and upstream are set only for the error extracted from a gRPC error.
+	domain   string
+	upstream []string
+}
+
+// Option is an option when creating the error.
+type Option func(*baseError)
+
+func newBaseError(cause error, code codes.Code, detailCode, message string, opts []Option) baseError {
+	e := baseError{
+		code:       code,
+		detailCode: detailCode,
+		message:    message,
+		cause:      cause,
+	}
+	for _, opt := range opts {
+		opt(&e)
+	}
+	if e.stack != nil {
+		// Skip callers, newBaseError and the constructor.
+		e.stack = callers(3)
+	}
+	return e
+}
+
+// Error is a method to satisfy the error interface.
+func (e *baseError) Error() string {
+	return e.message
+}
+
+// Unwrap returns the cause of the error.
+func (e *baseError) Unwrap() error {
+	return e.cause
+}
+
+// Is reports whether the error is regarded as `target` by errors.Is.
+// The errors are the same if their codes and detail codes are equal,
+// so that the error extracted from a gRPC error matches the package-level error.
+// The errors without the detail code (empty or NoDetailCode) never match,
+// because they do not identify the error.
+func (e *baseError) Is(target error) bool {
+	t, ok := target.(Err)
+	if !ok || e.detailCode == "" || e.detailCode == NoDetailCode {
+		return false
+	}
+	return e.code == t.Code() && e.detailCode == t.DetailCode()
+}
+
+// Code returns code value.
+func (e *baseError) Code() codes.Code {
+	return e.code
+}
+
+// DetailCode returns detail code string.
+func (e *baseError) DetailCode() string {
+	return e.detailCode
+}
+
+// Message returns message string.
+func (e *baseError) Message() string {
+	return e.message
+}
+
+// Details returns the error details.
+func (e *baseError) Details() Details {
+	return e.details.clone()
+}
+
+// Retryable returns whether the client may retry the request.
+// The error is retryable if the code is Unavailable, Aborted or ResourceExhausted,
+// or if the retry delay is specified by RetryDelayOption.
+func (e *baseError) Retryable() bool {
+	switch e.code {
+	case codes.Unavailable, codes.Aborted, codes.ResourceExhausted:
+		return true
+	}
+	return e.details.RetryDelay > 0
+}
+
+// RetryDelay returns the delay that the client should wait before retrying.
+// It returns 0 if the delay is not specified.
+func (e *baseError) RetryDelay() time.Duration {
+	return e.details.RetryDelay
+}
+
+// StackTrace returns the call stack captured at creation of the error.
+// It returns nil unless the error is created with StackTraceOption.
+func (e *baseError) StackTrace() StackTrace {
+	return e.stack
+}
+
+// Format formats the error according to the fmt.Formatter interface.
+// The verb %+v prints the message followed by the stack trace if captured,
+// and the other verbs format the message as a string (ex. %v, %s, %q, %x).
+func (e *baseError) Format(s fmt.State, verb rune) {
+	if verb == 'v' && s.Flag('+') && e.stack != nil {
+		fmt.Fprintf(s, "%s\n%s", e.message, e.stack)
+		return
+	}
+	fmt.Fprintf(s, fmt.FormatString(s, verb), e.message)
+}
+
+// HTTPStatus returns HTTP status code.
+func (e *baseError) HTTPStatus() int {
+	return runtime.HTTPStatusFromCode(e.code)
+}
+
+// Domain returns the domain of the service that last emitted the error as a gRPC error.
+// It is the service where the error was raised only if the error has not passed through
+// other services (see Upstream for the origin).
+// It is set only for the error extracted from a gRPC error, and empty otherwise.
+func (e *baseError) Domain() string {
+	return e.domain
+}
+
+// Upstream returns the domains of the services that the error has passed through,
+// in order from the origin. The last one is the same as Domain.
+// The domains before the last one are available only if the upstream service
+// emits the error with UpstreamChainOption.
+func (e *baseError) Upstream() []string {
+	return slices.Clone(e.upstream)
+}
+
+// GRPCError returns gRPC error.
+// The detail code and the error details are attached as gRPC error details.
+// Use ToGRPCError to specify the options.
+func (e *baseError) GRPCError(domain string) error {
+	return e.grpcError(domain, nil)
+}
+
+func (e *baseError) grpcError(domain string, opts []GRPCOption) error {
+	return e.grpcStatus(domain, opts).Err()
+}
+
+func (e *baseError) grpcStatus(domain string, opts []GRPCOption) *status.Status {
+	var o grpcOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	details := e.details
+	if o.upstreamChain && len(e.upstream) > 0 {
+		details.Metadata = maps.Clone(details.Metadata)
+		if details.Metadata == nil {
+			details.Metadata = map[string]string{}
+		}
+		details.Metadata[UpstreamMetadataKey] = strings.Join(e.upstream, ",")
+	}
+
+	st := status.New(e.Code(), e.Message())
+	st, _ = st.WithDetails(details.protoMessages(e.DetailCode(), domain)...)
+
+	return st
+}
@@ -0,0 +1,145 @@
+package apperr
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+)
+
+// Catalog is a message catalog that holds localized messages keyed by the detail code.
+// The messages are templates of text/template, and can refer to the metadata
+// of the error as parameters (ex. "{{.max}} 文字以内で入力してください").
+type Catalog struct {
+	mu        sync.RWMutex
+	templates map[string]map[string]localizedTemplate // detail code -> lower-case locale -> template
+}
+
+type localizedTemplate struct {
+	locale string
+	tmpl   *template.Template
+}
+
+// NewCatalog creates new empty message catalog.
+func NewCatalog() *Catalog {
+	return &Catalog{
+		templates: map[string]map[string]localizedTemplate{},
+	}
+}
+
+// Add adds the message of the detail code for the locale (ex. "ja", "en-US").
+// An error is returned if the message is not a valid template.
+func (c *Catalog) Add(detailCode, locale, message string) error {
+	tmpl, err := template.New(detailCode).Option("missingkey=zero").Parse(message)
+	if err != nil {
+		return fmt.Errorf("failed to parse message of %s (%s): %w", detailCode, locale, err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.templates[detailCode]; !ok {
+		c.templates[detailCode] = map[string]localizedTemplate{}
+	}
+	c.templates[detailCode][strings.ToLower(locale)] = localizedTemplate{locale: locale, tmpl: tmpl}
+	return nil
+}
+
+// MustAdd is like Add but panics if the message is not a valid template.
+// It simplifies the initialization of the catalog.
+func (c *Catalog) MustAdd(detailCode, locale, message string) *Catalog {
+	if err := c.Add(detailCode, locale, message); err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// Lookup returns the message of the error localized to the most preferred locale
+// in `acceptLanguage`, which is the value of Accept-Language header (ex. "ja,en-US;q=0.8").
+// A language tag also matches its base language (ex. "ja-JP" matches "ja").
+// It returns false if no message matches.
+func (c *Catalog) Lookup(e Err, acceptLanguage string) (LocalizedMessage, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	templates, ok := c.templates[e.DetailCode()]
+	if !ok {
+		return LocalizedMessage{}, false
+	}
+
+	for _, tag := range parseAcceptLanguage(acceptLanguage) {
+		t, ok := matchLocale(templates, tag)
+		if !ok {
+			continue
+		}
+		var b strings.Builder
+		if err := t.tmpl.Execute(&b, detailsOf(e).Metadata); err != nil {
+			continue
+		}
+		return LocalizedMessage{Locale: t.locale, Message: b.String()}, true
+	}
+	return LocalizedMessage{}, false
+}
+
+// Localize returns the error with the localized message (see Lookup) as errdetails.LocalizedMessage.
+// If no message matches, or the error is not created by this package, the error is returned as it is,
+// so that the client falls back to the default message.
+func (c *Catalog) Localize(e Err, acceptLanguage string) Err {
+	m, ok := c.Lookup(e, acceptLanguage)
+	if !ok {
+		return e
+	}
+	return withOptions(e, LocalizedMessageOption(m.Locale, m.Message))
+}
+
+func matchLocale(templates map[string]localizedTemplate, tag string) (localizedTemplate, bool) {
+	if t, ok := templates[tag]; ok {
+		return t, true
+	}
+	if base, _, found := strings.Cut(tag, "-"); found {
+		if t, ok := templates[base]; ok {
+			return t, true
+		}
+	}
+	return localizedTemplate{}, false
+}
+
+// parseAcceptLanguage returns the language tags in lower case in the order of preference.
+// The wildcard and the tags with q=0 are excluded.
+func parseAcceptLanguage(s string) []string {
+	type tag struct {
+		name string
+		q    float64
+	}
+	var tags []tag
+	for _, part := range strings.Split(s, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || name == "*" {
+			continue
+		}
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = f
+		}
+		if q <= 0 {
+			continue
+		}
+		tags = append(tags, tag{name: name, q: q})
+	}
+	sort.SliceStable(tags, func(i, j int) bool {
+		return tags[i].q > tags[j].q
+	})
+
+	names := make([]string, 0, len(tags))
+	for _, t := range tags {
+		names = append(names, t.name)
+	}
+	return names
+}
@@ -0,0 +1,70 @@
+package apperr_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/takuoki/golib/apperr"
+	"google.golang.org/grpc/codes"
+)
+
+func TestCatalog(t *testing.T) {
+	catalog := apperr.NewCatalog().
+		MustAdd("E0001", "ja", "見つかりません").
+		MustAdd("E0001", "en-US", "Not found").
+		MustAdd("E0002", "ja", "{{.field}} は {{.max}} 文字以内で入力してください")
+
+	notFound := apperr.NewClientError(codes.NotFound, "E0001", "not found")
+	tooLong := apperr.NewClientError(codes.InvalidArgument, "E0002", "too long",
+		apperr.MetadataOption(map[string]string{"field": "name", "max": "10"}))
+	unknown := apperr.NewClientError(codes.InvalidArgument, "E9999", "unknown")
+
+	testcases := map[string]struct {
+		err            apperr.Err
+		acceptLanguage string
+		want           apperr.LocalizedMessage
+		wantOK         bool
+	}{
+		"exact":         {err: notFound, acceptLanguage: "en-US", want: apperr.LocalizedMessage{Locale: "en-US", Message: "Not found"}, wantOK: true},
+		"base-language": {err: notFound, acceptLanguage: "ja-JP", want: apperr.LocalizedMessage{Locale: "ja", Message: "見つかりません"}, wantOK: true},
+		"quality":       {err: notFound, acceptLanguage: "ja;q=0.5, en-US;q=0.8", want: apperr.LocalizedMessage{Locale: "en-US", Message: "Not found"}, wantOK: true},
+		"second-choice": {err: notFound, acceptLanguage: "fr, ja;q=0.1", want: apperr.LocalizedMessage{Locale: "ja", Message: "見つかりません"}, wantOK: true},
+		"params":        {err: tooLong, acceptLanguage: "ja", want: apperr.LocalizedMessage{Locale: "ja", Message: "name は 10 文字以内で入力してください"}, wantOK: true},
+		"q-zero":        {err: notFound, acceptLanguage: "ja;q=0, fr"},
+		"no-match":      {err: notFound, acceptLanguage: "fr, *"},
+		"empty":         {err: notFound, acceptLanguage: ""},
+		"unknown-code":  {err: unknown, acceptLanguage: "ja"},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			m, ok := catalog.Lookup(tc.err, tc.acceptLanguage)
+			assert.Equal(t, tc.wantOK, ok, "ok is not equal.")
+			assert.Equal(t, tc.want, m, "LocalizedMessage is not equal.")
+		})
+	}
+}
+
+func TestCatalog_Localize(t *testing.T) {
+	catalog := apperr.NewCatalog().MustAdd("E0001", "ja", "見つかりません")
+	err := apperr.NewClientError(codes.NotFound, "E0001", "not found")
+
+	t.Run("match", func(t *testing.T) {
+		result := catalog.Localize(err, "ja-JP")
+		assert.Equal(t, "not found", result.Message(), "Message must be the default message.")
+		assert.Equal(t, &apperr.LocalizedMessage{Locale: "ja", Message: "見つかりません"}, result.(apperr.Detailer).Details().LocalizedMessage)
+		assert.Nil(t, err.(apperr.Detailer).Details().LocalizedMessage, "The original error must not be changed.")
+	})
+	t.Run("no-match", func(t *testing.T) {
+		result := catalog.Localize(err, "en")
+		assert.Equal(t, err, result, "The error must be returned as it is.")
+	})
+}
+
+func TestCatalog_Add(t *testing.T) {
+	err := apperr.NewCatalog().Add("E0001", "ja", "{{.field")
+	if assert.Error(t, err) {
+		assert.Regexp(t, `^failed to parse message of E0001 \(ja\): `, err.Error())
+	}
+	assert.Panics(t, func() { apperr.NewCatalog().MustAdd("E0001", "ja", "{{.field") })
+}
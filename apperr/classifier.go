@@ -0,0 +1,53 @@
+package apperr
+
+import (
+	"google.golang.org/grpc/codes"
+)
+
+// Classifier is a function that classifies the gRPC code into the error type.
+// Return 0 for the code that should not be extracted as an error.
+type Classifier func(code codes.Code) Type
+
+// DefaultClassifier is the default classifier of ExtractFromGRPCError.
+// The codes caused by the request (ex. InvalidArgument, NotFound, Canceled, ResourceExhausted)
+// are client errors, and the codes caused by the server (ex. Internal, Unavailable, DeadlineExceeded)
+// are server errors.
+func DefaultClassifier(code codes.Code) Type {
+	switch code {
+	case codes.PermissionDenied, codes.Unauthenticated,
+		codes.InvalidArgument, codes.NotFound, codes.AlreadyExists, codes.FailedPrecondition,
+		codes.Canceled, codes.ResourceExhausted, codes.Aborted, codes.OutOfRange:
+		return ClientError
+	case codes.Internal, codes.Unavailable, codes.Unimplemented,
+		codes.DeadlineExceeded, codes.DataLoss, codes.Unknown:
+		return ServerError
+	default: // codes.OK
+		return 0
+	}
+}
+
+// ClassifierMap returns the classifier that classifies the codes in the map,
+// and the other codes with DefaultClassifier.
+func ClassifierMap(m map[codes.Code]Type) Classifier {
+	return func(code codes.Code) Type {
+		if t, ok := m[code]; ok {
+			return t
+		}
+		return DefaultClassifier(code)
+	}
+}
+
+type extractOptions struct {
+	classifier Classifier
+}
+
+// ExtractOption is an option when extracting the error from a gRPC error.
+type ExtractOption func(*extractOptions)
+
+// ClassifierOption sets the classifier of the error type.
+// The default is DefaultClassifier.
+func ClassifierOption(c Classifier) ExtractOption {
+	return func(o *extractOptions) {
+		o.classifier = c
+	}
+}
@@ -0,0 +1,95 @@
+package apperr_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/takuoki/golib/apperr"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestParseGRPCError(t *testing.T) {
+	notGRPC := errors.New("error")
+
+	testcases := map[string]struct {
+		err      error
+		opts     []apperr.ExtractOption
+		wantType apperr.Type
+		wantErr  error
+		wantMsg  string
+	}{
+		"client": {
+			err:      status.Error(codes.Canceled, "canceled"),
+			wantType: apperr.ClientError,
+		},
+		"server": {
+			err:      status.Error(codes.DeadlineExceeded, "deadline exceeded"),
+			wantType: apperr.ServerError,
+		},
+		"classifier": {
+			err: status.Error(codes.DeadlineExceeded, "deadline exceeded"),
+			opts: []apperr.ExtractOption{apperr.ClassifierOption(apperr.ClassifierMap(map[codes.Code]apperr.Type{
+				codes.DeadlineExceeded: apperr.ClientError,
+			}))},
+			wantType: apperr.ClientError,
+		},
+		"classifier-fallback": {
+			err: status.Error(codes.Internal, "internal"),
+			opts: []apperr.ExtractOption{apperr.ClassifierOption(apperr.ClassifierMap(map[codes.Code]apperr.Type{
+				codes.DeadlineExceeded: apperr.ClientError,
+			}))},
+			wantType: apperr.ServerError,
+		},
+		"unclassified": {
+			err: status.Error(codes.Canceled, "canceled"),
+			opts: []apperr.ExtractOption{apperr.ClassifierOption(func(code codes.Code) apperr.Type {
+				return 0
+			})},
+			wantErr: apperr.ErrUnclassified,
+			wantMsg: "gRPC code is not classified: Canceled",
+		},
+		"ok": {
+			err:     status.Error(codes.OK, ""),
+			wantErr: apperr.ErrOKStatus,
+			wantMsg: "gRPC status is OK",
+		},
+		"nil": {
+			err:     nil,
+			wantErr: apperr.ErrOKStatus,
+			wantMsg: "gRPC status is OK",
+		},
+		"not-grpc": {
+			err:     notGRPC,
+			wantErr: apperr.ErrNotGRPCStatus,
+			wantMsg: "error is not a gRPC status: error",
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			e, err := apperr.ParseGRPCError(tc.err, tc.opts...)
+			_, ok := apperr.ExtractFromGRPCError(tc.err, tc.opts...)
+			if tc.wantErr != nil {
+				assert.Nil(t, e, "Err is not nil.")
+				assert.False(t, ok, "Ok of ExtractFromGRPCError is not false.")
+				if assert.Error(t, err) {
+					assert.True(t, errors.Is(err, tc.wantErr), "errors.Is is not true: %v", err)
+					assert.Equal(t, tc.wantMsg, err.Error())
+				}
+				return
+			}
+			assert.NoError(t, err)
+			assert.True(t, ok, "Ok of ExtractFromGRPCError is not true.")
+			if assert.NotNil(t, e, "Err is nil.") {
+				assert.Equal(t, tc.wantType, e.Type(), "Type is not equal.")
+			}
+		})
+	}
+
+	t.Run("not-grpc-wraps-error", func(t *testing.T) {
+		_, err := apperr.ParseGRPCError(notGRPC)
+		assert.True(t, errors.Is(err, notGRPC), "The original error must be wrapped.")
+	})
+}
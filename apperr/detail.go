@@ -0,0 +1,295 @@
+package apperr
+
+import (
+	"maps"
+	"slices"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// Details is the additional information of the error.
+// Each item is converted to the corresponding gRPC error detail by GRPCError.
+type Details struct {
+	// FieldViolations is converted to errdetails.BadRequest.
+	FieldViolations []FieldViolation `json:"violations,omitempty"`
+	// Metadata is converted to the metadata of errdetails.ErrorInfo.
+	Metadata map[string]string `json:"metadata,omitempty"`
+	// RetryDelay is converted to errdetails.RetryInfo. 0 means no retry information.
+	RetryDelay time.Duration `json:"-"`
+	// QuotaViolations is converted to errdetails.QuotaFailure.
+	QuotaViolations []QuotaViolation `json:"quota_violations,omitempty"`
+	// PreconditionViolations is converted to errdetails.PreconditionFailure.
+	PreconditionViolations []PreconditionViolation `json:"precondition_violations,omitempty"`
+	// LocalizedMessage is converted to errdetails.LocalizedMessage.
+	LocalizedMessage *LocalizedMessage `json:"localized_message,omitempty"`
+	// HelpLinks is converted to errdetails.Help.
+	HelpLinks []HelpLink `json:"help_links,omitempty"`
+}
+
+// FieldViolation describes a single bad request field.
+type FieldViolation struct {
+	Field       string `json:"field"`
+	Reason      string `json:"reason,omitempty"`
+	Description string `json:"description"`
+}
+
+// QuotaViolation describes a single quota violation.
+type QuotaViolation struct {
+	Subject     string `json:"subject"`
+	Description string `json:"description"`
+}
+
+// PreconditionViolation describes a single precondition failure.
+type PreconditionViolation struct {
+	Type        string `json:"type"`
+	Subject     string `json:"subject"`
+	Description string `json:"description"`
+}
+
+// LocalizedMessage is an error message localized to the locale (ex. "ja-JP").
+type LocalizedMessage struct {
+	Locale  string `json:"locale"`
+	Message string `json:"message"`
+}
+
+// HelpLink is a link to the documentation of the error.
+type HelpLink struct {
+	Description string `json:"description"`
+	URL         string `json:"url"`
+}
+
+// FieldViolationOption adds a bad request field violation.
+// Set the reason code of the violation (ex. "REQUIRED") to `reason`.
+func FieldViolationOption(field, reason, description string) Option {
+	return func(e *baseError) {
+		e.details.FieldViolations = append(e.details.FieldViolations, FieldViolation{
+			Field:       field,
+			Reason:      reason,
+			Description: description,
+		})
+	}
+}
+
+// MetadataOption adds the metadata of the error.
+// The key UpstreamMetadataKey is reserved by this package.
+func MetadataOption(md map[string]string) Option {
+	return func(e *baseError) {
+		if e.details.Metadata == nil {
+			e.details.Metadata = make(map[string]string, len(md))
+		}
+		maps.Copy(e.details.Metadata, md)
+	}
+}
+
+// RetryDelayOption sets the delay that the client should wait before retrying.
+func RetryDelayOption(delay time.Duration) Option {
+	return func(e *baseError) {
+		e.details.RetryDelay = delay
+	}
+}
+
+// QuotaViolationOption adds a quota violation.
+func QuotaViolationOption(subject, description string) Option {
+	return func(e *baseError) {
+		e.details.QuotaViolations = append(e.details.QuotaViolations, QuotaViolation{
+			Subject:     subject,
+			Description: description,
+		})
+	}
+}
+
+// PreconditionViolationOption adds a precondition violation.
+// Set the type of the precondition (ex. "TOS") to `typ`.
+func PreconditionViolationOption(typ, subject, description string) Option {
+	return func(e *baseError) {
+		e.details.PreconditionViolations = append(e.details.PreconditionViolations, PreconditionViolation{
+			Type:        typ,
+			Subject:     subject,
+			Description: description,
+		})
+	}
+}
+
+// LocalizedMessageOption sets the error message localized to the locale.
+func LocalizedMessageOption(locale, message string) Option {
+	return func(e *baseError) {
+		e.details.LocalizedMessage = &LocalizedMessage{
+			Locale:  locale,
+			Message: message,
+		}
+	}
+}
+
+// HelpLinkOption adds a link to the documentation of the error.
+func HelpLinkOption(description, url string) Option {
+	return func(e *baseError) {
+		e.details.HelpLinks = append(e.details.HelpLinks, HelpLink{
+			Description: description,
+			URL:         url,
+		})
+	}
+}
+
+func (d Details) clone() Details {
+	d.FieldViolations = slices.Clone(d.FieldViolations)
+	d.Metadata = maps.Clone(d.Metadata)
+	d.QuotaViolations = slices.Clone(d.QuotaViolations)
+	d.PreconditionViolations = slices.Clone(d.PreconditionViolations)
+	if d.LocalizedMessage != nil {
+		m := *d.LocalizedMessage
+		d.LocalizedMessage = &m
+	}
+	d.HelpLinks = slices.Clone(d.HelpLinks)
+	return d
+}
+
+// protoMessages converts the details to gRPC error details.
+// errdetails.ErrorInfo is always included to hold the detail code.
+func (d Details) protoMessages(detailCode, domain string) []protoadapt.MessageV1 {
+	msgs := []protoadapt.MessageV1{&errdetails.ErrorInfo{
+		Reason:   detailCode,
+		Domain:   domain,
+		Metadata: d.Metadata,
+	}}
+
+	if len(d.FieldViolations) > 0 {
+		br := &errdetails.BadRequest{}
+		for _, v := range d.FieldViolations {
+			br.FieldViolations = append(br.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       v.Field,
+				Reason:      v.Reason,
+				Description: v.Description,
+			})
+		}
+		msgs = append(msgs, br)
+	}
+	if d.RetryDelay > 0 {
+		msgs = append(msgs, &errdetails.RetryInfo{RetryDelay: durationpb.New(d.RetryDelay)})
+	}
+	if len(d.QuotaViolations) > 0 {
+		qf := &errdetails.QuotaFailure{}
+		for _, v := range d.QuotaViolations {
+			qf.Violations = append(qf.Violations, &errdetails.QuotaFailure_Violation{
+				Subject:     v.Subject,
+				Description: v.Description,
+			})
+		}
+		msgs = append(msgs, qf)
+	}
+	if len(d.PreconditionViolations) > 0 {
+		pf := &errdetails.PreconditionFailure{}
+		for _, v := range d.PreconditionViolations {
+			pf.Violations = append(pf.Violations, &errdetails.PreconditionFailure_Violation{
+				Type:        v.Type,
+				Subject:     v.Subject,
+				Description: v.Description,
+			})
+		}
+		msgs = append(msgs, pf)
+	}
+	if d.LocalizedMessage != nil {
+		msgs = append(msgs, &errdetails.LocalizedMessage{
+			Locale:  d.LocalizedMessage.Locale,
+			Message: d.LocalizedMessage.Message,
+		})
+	}
+	if len(d.HelpLinks) > 0 {
+		h := &errdetails.Help{}
+		for _, l := range d.HelpLinks {
+			h.Links = append(h.Links, &errdetails.Help_Link{
+				Description: l.Description,
+				Url:         l.URL,
+			})
+		}
+		msgs = append(msgs, h)
+	}
+	return msgs
+}
+
+// detailsFromProto reads the detail code, the domain and the details from gRPC error details.
+// The order of the details does not matter, and unknown details are ignored.
+func detailsFromProto(protoDetails []any) (string, string, Details) {
+	var (
+		detailCode string
+		domain     string
+		d          Details
+	)
+	for _, pd := range protoDetails {
+		switch v := pd.(type) {
+		case *errdetails.ErrorInfo:
+			detailCode = v.GetReason()
+			domain = v.GetDomain()
+			d.Metadata = maps.Clone(v.GetMetadata())
+		case *errdetails.BadRequest:
+			for _, fv := range v.GetFieldViolations() {
+				d.FieldViolations = append(d.FieldViolations, FieldViolation{
+					Field:       fv.GetField(),
+					Reason:      fv.GetReason(),
+					Description: fv.GetDescription(),
+				})
+			}
+		case *errdetails.RetryInfo:
+			d.RetryDelay = v.GetRetryDelay().AsDuration()
+		case *errdetails.QuotaFailure:
+			for _, qv := range v.GetViolations() {
+				d.QuotaViolations = append(d.QuotaViolations, QuotaViolation{
+					Subject:     qv.GetSubject(),
+					Description: qv.GetDescription(),
+				})
+			}
+		case *errdetails.PreconditionFailure:
+			for _, pv := range v.GetViolations() {
+				d.PreconditionViolations = append(d.PreconditionViolations, PreconditionViolation{
+					Type:        pv.GetType(),
+					Subject:     pv.GetSubject(),
+					Description: pv.GetDescription(),
+				})
+			}
+		case *errdetails.LocalizedMessage:
+			d.LocalizedMessage = &LocalizedMessage{
+				Locale:  v.GetLocale(),
+				Message: v.GetMessage(),
+			}
+		case *errdetails.Help:
+			for _, l := range v.GetLinks() {
+				d.HelpLinks = append(d.HelpLinks, HelpLink{
+					Description: l.GetDescription(),
+					URL:         l.GetUrl(),
+				})
+			}
+		}
+	}
+	return detailCode, domain, d
+}
+
+// detailsOption returns an option that sets all the details.
+func detailsOption(details Details) Option {
+	return func(e *baseError) {
+		e.details = details
+	}
+}
+
+// withOptions returns a copy of the error with the options applied.
+// The error that is not created by this package is returned as it is.
+func withOptions(e Err, opts ...Option) Err {
+	switch v := e.(type) {
+	case *clientError:
+		c := *v
+		c.details = c.details.clone()
+		for _, opt := range opts {
+			opt(&c.baseError)
+		}
+		return &c
+	case *serverError:
+		c := *v
+		c.details = c.details.clone()
+		for _, opt := range opts {
+			opt(&c.baseError)
+		}
+		return &c
+	}
+	return e
+}
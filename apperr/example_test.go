@@ -76,6 +76,38 @@ func ExampleExtractFromGRPCError() {
 	// Code: Internal, DetailCode: S0001, Message: internal server error
 }
 
+func ExampleValidation() {
+
+	validate := func(name string, age int) error {
+		v := apperr.NewValidation("E0100", "invalid request")
+		if name == "" {
+			v.AddFieldViolation("name", "E0101", "name is required")
+		}
+		if age < 0 {
+			v.AddFieldViolation("age", "E0102", "age must be positive")
+		}
+		if err := v.Err(); err != nil {
+			return err
+		}
+		return nil
+	}
+
+	err := validate("", -1)
+	if e, ok := apperr.Extract(err); ok {
+		fmt.Printf("Code: %s, DetailCode: %s, Message: %s\n", e.Code(), e.DetailCode(), e.Message())
+		if d, ok := e.(apperr.Detailer); ok {
+			for _, v := range d.Details().FieldViolations {
+				fmt.Printf("Field: %s, DetailCode: %s, Message: %s\n", v.Field, v.Reason, v.Description)
+			}
+		}
+	}
+
+	// Output:
+	// Code: InvalidArgument, DetailCode: E0100, Message: invalid request
+	// Field: name, DetailCode: E0101, Message: name is required
+	// Field: age, DetailCode: E0102, Message: age must be positive
+}
+
 // The following is assumed to be defined in each application.
 
 var (
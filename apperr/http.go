@@ -0,0 +1,130 @@
+package apperr
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc/codes"
+)
+
+// MIMEApplicationProblemJSON is the content type of RFC 9457 Problem Details.
+const MIMEApplicationProblemJSON = "application/problem+json"
+
+// maxHTTPBodySize is the maximum size of the response body read by ExtractFromHTTPResponse.
+const maxHTTPBodySize = 1 << 20
+
+// httpStatusCodes is the table to convert HTTP status to gRPC code.
+// 499 is the status that grpc-gateway uses for codes.Canceled.
+var httpStatusCodes = map[int]codes.Code{
+	http.StatusOK:                    codes.OK,
+	http.StatusCreated:               codes.OK,
+	http.StatusAccepted:              codes.OK,
+	http.StatusNoContent:             codes.OK,
+	http.StatusBadRequest:            codes.InvalidArgument,
+	http.StatusUnauthorized:          codes.Unauthenticated,
+	http.StatusForbidden:             codes.PermissionDenied,
+	http.StatusNotFound:              codes.NotFound,
+	http.StatusMethodNotAllowed:      codes.Unimplemented,
+	http.StatusRequestTimeout:        codes.Canceled,
+	http.StatusConflict:              codes.AlreadyExists,
+	http.StatusPreconditionFailed:    codes.FailedPrecondition,
+	http.StatusRequestEntityTooLarge: codes.InvalidArgument,
+	http.StatusUnsupportedMediaType:  codes.InvalidArgument,
+	http.StatusUnprocessableEntity:   codes.InvalidArgument,
+	http.StatusTooManyRequests:       codes.ResourceExhausted,
+	499:                              codes.Canceled,
+	http.StatusInternalServerError:   codes.Internal,
+	http.StatusNotImplemented:        codes.Unimplemented,
+	http.StatusBadGateway:            codes.Unavailable,
+	http.StatusServiceUnavailable:    codes.Unavailable,
+	http.StatusGatewayTimeout:        codes.DeadlineExceeded,
+}
+
+// CodeFromHTTPStatus converts HTTP status to gRPC code.
+// It returns false if the status is not in the conversion table.
+func CodeFromHTTPStatus(status int) (codes.Code, bool) {
+	code, ok := httpStatusCodes[status]
+	return code, ok
+}
+
+// problemJSON is the body of RFC 9457 Problem Details written by echo_error.
+type problemJSON struct {
+	Title    string `json:"title"`
+	Detail   string `json:"detail"`
+	Code     string `json:"code"`
+	GRPCCode string `json:"grpc_code"`
+	Details
+	RetryDelay float64 `json:"retry_delay"`
+}
+
+// ExtractFromHTTPResponse is a function to extract apperr.Err from an HTTP error response.
+// The body in the JSON format of this package, echo_error (ex. {"code":"E0001","message":"not found"})
+// or Problem Details (application/problem+json) is supported.
+// The code is taken from "grpc_code" of the body, or converted from the HTTP status
+// (codes.Unknown for the status not in the conversion table), and the type is classified
+// with the classifier. The retry delay is also taken from Retry-After header.
+// It returns false if the status is not an error (less than 400).
+// The body is read, and replaced so that it can be read again.
+func ExtractFromHTTPResponse(resp *http.Response, opts ...ExtractOption) (Err, bool) {
+	if resp == nil || resp.StatusCode < http.StatusBadRequest {
+		return nil, false
+	}
+	o := extractOptions{classifier: DefaultClassifier}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var body []byte
+	if resp.Body != nil {
+		body, _ = io.ReadAll(io.LimitReader(resp.Body, maxHTTPBodySize))
+		_ = resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	v := errorJSON{Message: http.StatusText(resp.StatusCode)}
+	if mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); mediaType == MIMEApplicationProblemJSON {
+		var p problemJSON
+		if err := json.Unmarshal(body, &p); err == nil {
+			v.Code, v.GRPCCode, v.Details, v.RetryDelay = p.Code, p.GRPCCode, p.Details, p.RetryDelay
+			if p.Detail != "" {
+				v.Message = p.Detail
+			} else if p.Title != "" {
+				v.Message = p.Title
+			}
+		}
+	} else {
+		_ = json.Unmarshal(body, &v)
+	}
+
+	code, ok := parseCode(v.GRPCCode)
+	if !ok {
+		if code, ok = CodeFromHTTPStatus(resp.StatusCode); !ok {
+			code = codes.Unknown
+		}
+	}
+	typ := parseType(v.Type)
+	if typ == 0 {
+		typ = o.classifier(code)
+	}
+
+	opt := []Option{detailsOption(v.details())}
+	if v.RetryDelay <= 0 {
+		if sec, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && sec > 0 {
+			opt = append(opt, RetryDelayOption(time.Duration(sec)*time.Second))
+		}
+	}
+
+	switch typ {
+	case ClientError:
+		return NewClientError(code, v.Code, v.Message, opt...), true
+	case ServerError:
+		return NewServerError(code, v.Code, v.Message, "http error is a server error", opt...), true
+	default:
+		return nil, false
+	}
+}
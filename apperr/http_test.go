@@ -0,0 +1,168 @@
+package apperr_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/takuoki/golib/apperr"
+	"google.golang.org/grpc/codes"
+)
+
+func TestCodeFromHTTPStatus(t *testing.T) {
+	code, ok := apperr.CodeFromHTTPStatus(http.StatusNotFound)
+	assert.True(t, ok)
+	assert.Equal(t, codes.NotFound, code)
+
+	code, ok = apperr.CodeFromHTTPStatus(499)
+	assert.True(t, ok)
+	assert.Equal(t, codes.Canceled, code)
+
+	_, ok = apperr.CodeFromHTTPStatus(http.StatusTeapot)
+	assert.False(t, ok)
+}
+
+func TestExtractFromHTTPResponse(t *testing.T) {
+	testcases := map[string]struct {
+		status      int
+		contentType string
+		header      map[string]string
+		body        string
+		opts        []apperr.ExtractOption
+		wantOK      bool
+		wantType    apperr.Type
+		wantCode    codes.Code
+		wantDetail  string
+		wantMessage string
+		wantDetails apperr.Details
+	}{
+		"echo-json": {
+			status:      http.StatusNotFound,
+			contentType: "application/json; charset=UTF-8",
+			body:        `{"code":"E0001","message":"not found"}`,
+			wantOK:      true,
+			wantType:    apperr.ClientError,
+			wantCode:    codes.NotFound,
+			wantDetail:  "E0001",
+			wantMessage: "not found",
+		},
+		"echo-json-with-details": {
+			status:      http.StatusBadRequest,
+			contentType: "application/json",
+			body:        `{"code":"E0100","message":"invalid request","violations":[{"field":"name","reason":"E0101","description":"name is required"}]}`,
+			wantOK:      true,
+			wantType:    apperr.ClientError,
+			wantCode:    codes.InvalidArgument,
+			wantDetail:  "E0100",
+			wantMessage: "invalid request",
+			wantDetails: apperr.Details{FieldViolations: []apperr.FieldViolation{{Field: "name", Reason: "E0101", Description: "name is required"}}},
+		},
+		"apperr-json": {
+			status:      http.StatusBadRequest,
+			contentType: "application/json",
+			body:        `{"type":"client-error","grpc_code":"FailedPrecondition","code":"E0002","message":"failed precondition"}`,
+			wantOK:      true,
+			wantType:    apperr.ClientError,
+			wantCode:    codes.FailedPrecondition,
+			wantDetail:  "E0002",
+			wantMessage: "failed precondition",
+		},
+		"problem-details": {
+			status:      http.StatusServiceUnavailable,
+			contentType: "application/problem+json",
+			body:        `{"type":"https://example.com/errors/S0002","title":"Service Unavailable","status":503,"detail":"unavailable","code":"S0002","retry_delay":3}`,
+			wantOK:      true,
+			wantType:    apperr.ServerError,
+			wantCode:    codes.Unavailable,
+			wantDetail:  "S0002",
+			wantMessage: "unavailable",
+			wantDetails: apperr.Details{RetryDelay: 3 * time.Second},
+		},
+		"retry-after": {
+			status:      http.StatusTooManyRequests,
+			contentType: "application/json",
+			header:      map[string]string{"Retry-After": "10"},
+			body:        `{"code":"E0003","message":"too many requests"}`,
+			wantOK:      true,
+			wantType:    apperr.ClientError,
+			wantCode:    codes.ResourceExhausted,
+			wantDetail:  "E0003",
+			wantMessage: "too many requests",
+			wantDetails: apperr.Details{RetryDelay: 10 * time.Second},
+		},
+		"not-json": {
+			status:      http.StatusBadGateway,
+			contentType: "text/html",
+			body:        `<html>bad gateway</html>`,
+			wantOK:      true,
+			wantType:    apperr.ServerError,
+			wantCode:    codes.Unavailable,
+			wantMessage: "Bad Gateway",
+		},
+		"unknown-status": {
+			status:      http.StatusTeapot,
+			wantOK:      true,
+			wantType:    apperr.ServerError,
+			wantCode:    codes.Unknown,
+			wantMessage: "I'm a teapot",
+		},
+		"classifier": {
+			status: http.StatusGatewayTimeout,
+			opts: []apperr.ExtractOption{apperr.ClassifierOption(apperr.ClassifierMap(map[codes.Code]apperr.Type{
+				codes.DeadlineExceeded: apperr.ClientError,
+			}))},
+			wantOK:      true,
+			wantType:    apperr.ClientError,
+			wantCode:    codes.DeadlineExceeded,
+			wantMessage: "Gateway Timeout",
+		},
+		"success": {
+			status: http.StatusOK,
+			body:   `{"code":"E0001","message":"not found"}`,
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			if tc.contentType != "" {
+				rec.Header().Set("Content-Type", tc.contentType)
+			}
+			for k, v := range tc.header {
+				rec.Header().Set(k, v)
+			}
+			rec.WriteHeader(tc.status)
+			_, _ = rec.WriteString(tc.body)
+			resp := rec.Result()
+
+			e, ok := apperr.ExtractFromHTTPResponse(resp, tc.opts...)
+			if !tc.wantOK {
+				assert.False(t, ok, "Ok is not false.")
+				assert.Nil(t, e, "Err is not nil.")
+				return
+			}
+			if !assert.True(t, ok, "Ok is not true.") {
+				return
+			}
+			assert.Equal(t, tc.wantType, e.Type(), "Type is not equal.")
+			assert.Equal(t, tc.wantCode, e.Code(), "Code is not equal.")
+			assert.Equal(t, tc.wantDetail, e.DetailCode(), "DetailCode is not equal.")
+			assert.Equal(t, tc.wantMessage, e.Message(), "Message is not equal.")
+			assert.Equal(t, tc.wantDetails, e.(apperr.Detailer).Details(), "Details is not equal.")
+
+			b, err := io.ReadAll(resp.Body)
+			if assert.NoError(t, err) {
+				assert.Equal(t, tc.body, string(b), "The body must be readable again.")
+			}
+		})
+	}
+
+	t.Run("nil", func(t *testing.T) {
+		e, ok := apperr.ExtractFromHTTPResponse(nil)
+		assert.False(t, ok)
+		assert.Nil(t, e)
+	})
+}
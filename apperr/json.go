@@ -0,0 +1,150 @@
+package apperr
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc/codes"
+)
+
+// errorJSON is the JSON representation of the error.
+// The error details are inlined in the same way as the response body of echo_error.
+type errorJSON struct {
+	Type     string `json:"type,omitempty"`
+	GRPCCode string `json:"grpc_code,omitempty"`
+	Code     string `json:"code"`
+	Message  string `json:"message"`
+	Domain   string `json:"domain,omitempty"`
+	Details
+	// RetryDelay is the retry delay in seconds.
+	RetryDelay float64 `json:"retry_delay,omitempty"`
+	// Errors is the aggregated errors of MultiErr.
+	Errors []json.RawMessage `json:"errors,omitempty"`
+}
+
+func newErrorJSON(e Err) errorJSON {
+	d := detailsOf(e)
+	return errorJSON{
+		Type:       e.Type().String(),
+		GRPCCode:   e.Code().String(),
+		Code:       e.DetailCode(),
+		Message:    e.Message(),
+		Domain:     domainOf(e),
+		Details:    d,
+		RetryDelay: d.RetryDelay.Seconds(),
+	}
+}
+
+func (v errorJSON) details() Details {
+	d := v.Details
+	d.RetryDelay = time.Duration(v.RetryDelay * float64(time.Second))
+	return d
+}
+
+// MarshalJSON is a method to satisfy the json.Marshaler interface.
+// The log and the cause are not included because they are internal information.
+func (e *clientError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(newErrorJSON(e))
+}
+
+// MarshalJSON is a method to satisfy the json.Marshaler interface.
+// The log and the cause are not included because they are internal information.
+func (e *serverError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(newErrorJSON(e))
+}
+
+// UnmarshalJSON is a method to satisfy the json.Unmarshaler interface.
+// The type in the JSON is ignored, use UnmarshalJSON function to restore the type as well.
+func (e *baseError) UnmarshalJSON(b []byte) error {
+	var v errorJSON
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	code, ok := parseCode(v.GRPCCode)
+	if !ok {
+		return fmt.Errorf("invalid grpc_code: %q", v.GRPCCode)
+	}
+	*e = newBaseError(nil, code, v.Code, v.Message, []Option{
+		detailsOption(v.details()),
+		originOption(v.Domain, nil),
+	})
+	return nil
+}
+
+// UnmarshalJSON is a method to satisfy the json.Unmarshaler interface.
+// The log is not included in the JSON, so a fixed log is set in the same way as ParseGRPCError.
+func (e *serverError) UnmarshalJSON(b []byte) error {
+	if err := e.baseError.UnmarshalJSON(b); err != nil {
+		return err
+	}
+	e.log = "json error is a server error"
+	return nil
+}
+
+// UnmarshalJSON is a function to restore apperr.Err from the JSON marshaled by the error.
+// If the type in the JSON is unknown, it is classified by DefaultClassifier.
+func UnmarshalJSON(b []byte) (Err, error) {
+	var v errorJSON
+	if err := json.Unmarshal(b, &v); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal error: %w", err)
+	}
+	typ := parseType(v.Type)
+	if typ == 0 {
+		code, _ := parseCode(v.GRPCCode)
+		typ = DefaultClassifier(code)
+	}
+
+	if len(v.Errors) > 0 && (typ == ClientError || typ == ServerError) {
+		return unmarshalMultiJSON(b, typ, v.Errors)
+	}
+
+	var e Err
+	switch typ {
+	case ClientError:
+		e = &clientError{}
+	case ServerError:
+		e = &serverError{}
+	default:
+		return nil, fmt.Errorf("failed to unmarshal error: unknown type: %q", v.Type)
+	}
+	if err := json.Unmarshal(b, e); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal error: %w", err)
+	}
+	return e, nil
+}
+
+func unmarshalMultiJSON(b []byte, typ Type, items []json.RawMessage) (Err, error) {
+	e := &multiError{typ: typ}
+	if err := json.Unmarshal(b, &e.baseError); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal error: %w", err)
+	}
+	for _, item := range items {
+		ie, err := UnmarshalJSON(item)
+		if err != nil {
+			return nil, err
+		}
+		e.errs = append(e.errs, ie)
+	}
+	return e, nil
+}
+
+// parseCode parses the string returned by codes.Code.String.
+func parseCode(s string) (codes.Code, bool) {
+	for c := codes.OK; c <= codes.Unauthenticated; c++ {
+		if c.String() == s {
+			return c, true
+		}
+	}
+	return 0, false
+}
+
+// parseType parses the string returned by Type.String.
+func parseType(s string) Type {
+	for _, t := range []Type{ClientError, ServerError} {
+		if t.String() == s {
+			return t
+		}
+	}
+	return 0
+}
@@ -0,0 +1,122 @@
+package apperr_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/takuoki/golib/apperr"
+	"google.golang.org/grpc/codes"
+)
+
+func TestMarshalJSON(t *testing.T) {
+	testcases := map[string]struct {
+		err  apperr.Err
+		want string
+	}{
+		"client": {
+			err:  apperr.NewClientError(codes.NotFound, "E0001", "not found"),
+			want: `{"type":"client-error","grpc_code":"NotFound","code":"E0001","message":"not found"}`,
+		},
+		"server": {
+			err: apperr.WrapServerError(errors.New("db is down"), codes.Unavailable, "S0001", "unavailable", "",
+				apperr.RetryDelayOption(1500*time.Millisecond)),
+			want: `{"type":"server-error","grpc_code":"Unavailable","code":"S0001","message":"unavailable","retry_delay":1.5}`,
+		},
+		"details": {
+			err: apperr.NewValidation("E0100", "invalid request").
+				AddFieldViolation("name", "E0101", "name is required").
+				Err(),
+			want: `{"type":"client-error","grpc_code":"InvalidArgument","code":"E0100","message":"invalid request",` +
+				`"violations":[{"field":"name","reason":"E0101","description":"name is required"}]}`,
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			b, err := json.Marshal(tc.err)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, tc.want, string(b))
+
+			result, err := apperr.UnmarshalJSON(b)
+			if assert.NoError(t, err) {
+				assert.Equal(t, tc.err.Type(), result.Type(), "Type is not equal.")
+				assert.Equal(t, tc.err.Code(), result.Code(), "Code is not equal.")
+				assert.Equal(t, tc.err.DetailCode(), result.DetailCode(), "DetailCode is not equal.")
+				assert.Equal(t, tc.err.Message(), result.Message(), "Message is not equal.")
+				assert.Equal(t, tc.err.(apperr.Detailer).Details(), result.(apperr.Detailer).Details(), "Details is not equal.")
+				assert.True(t, errors.Is(result, tc.err), "errors.Is is not true.")
+			}
+		})
+	}
+}
+
+func TestUnmarshalJSON(t *testing.T) {
+	testcases := map[string]struct {
+		in       string
+		wantType apperr.Type
+		wantCode codes.Code
+		wantLog  string
+		wantErr  string
+	}{
+		"client": {
+			in:       `{"type":"client-error","grpc_code":"NotFound","code":"E0001","message":"not found"}`,
+			wantType: apperr.ClientError,
+			wantCode: codes.NotFound,
+		},
+		"server": {
+			in:       `{"type":"server-error","grpc_code":"Internal","code":"S0001","message":"internal"}`,
+			wantType: apperr.ServerError,
+			wantCode: codes.Internal,
+			wantLog:  "json error is a server error",
+		},
+		"unknown-type": {
+			in:       `{"grpc_code":"Unavailable","code":"S0001","message":"unavailable"}`,
+			wantType: apperr.ServerError,
+			wantCode: codes.Unavailable,
+			wantLog:  "json error is a server error",
+		},
+		"invalid-grpc-code": {
+			in:      `{"type":"client-error","grpc_code":"NOT_FOUND","code":"E0001","message":"not found"}`,
+			wantErr: `failed to unmarshal error: invalid grpc_code: "NOT_FOUND"`,
+		},
+		"ok": {
+			in:      `{"grpc_code":"OK","code":"E0001","message":"ok"}`,
+			wantErr: `failed to unmarshal error: unknown type: ""`,
+		},
+		"invalid-json": {
+			in:      `{`,
+			wantErr: "failed to unmarshal error: unexpected end of JSON input",
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			e, err := apperr.UnmarshalJSON([]byte(tc.in))
+			if tc.wantErr != "" {
+				if assert.Error(t, err) {
+					assert.Equal(t, tc.wantErr, err.Error())
+				}
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, tc.wantType, e.Type(), "Type is not equal.")
+				assert.Equal(t, tc.wantCode, e.Code(), "Code is not equal.")
+				assert.Equal(t, tc.wantLog, e.Log(), "Log is not equal.")
+			}
+		})
+	}
+
+	t.Run("method", func(t *testing.T) {
+		e := apperr.NewClientError(codes.Internal, "", "")
+		if assert.NoError(t, json.Unmarshal([]byte(`{"grpc_code":"NotFound","code":"E0001","message":"not found","domain":"example.com"}`), e)) {
+			assert.Equal(t, codes.NotFound, e.Code(), "Code is not equal.")
+			assert.Equal(t, "E0001", e.DetailCode(), "DetailCode is not equal.")
+			assert.Equal(t, "example.com", e.(apperr.Originator).Domain(), "Domain is not equal.")
+		}
+	})
+}
@@ -0,0 +1,216 @@
+package apperr
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+// MultiErr is an error that aggregates multiple errors.
+// The code, the detail code, the message and the type are those of the error
+// with the highest precedence among the aggregated errors.
+type MultiErr interface {
+	Err
+	// Errors returns the aggregated errors.
+	Errors() []Err
+	// Unwrap returns the aggregated errors for errors.Is and errors.As.
+	Unwrap() []error
+}
+
+// Precedence is the order of the gRPC codes to decide the overall code of the aggregate error.
+// The codes not in the precedence come after the codes in it.
+type Precedence []codes.Code
+
+// DefaultPrecedence is the default precedence of the aggregate error.
+// Server errors take precedence over client errors.
+var DefaultPrecedence = Precedence{
+	codes.Internal, codes.DataLoss, codes.Unknown, codes.Unavailable, codes.DeadlineExceeded, codes.Unimplemented,
+	codes.Unauthenticated, codes.PermissionDenied, codes.ResourceExhausted, codes.FailedPrecondition,
+	codes.Aborted, codes.NotFound, codes.AlreadyExists, codes.OutOfRange, codes.InvalidArgument, codes.Canceled,
+}
+
+// Multi is a builder of the aggregate error.
+// The zero value cannot be used, create it with NewMulti.
+type Multi struct {
+	precedence         Precedence
+	internalDetailCode string
+	errs               []Err
+}
+
+// MultiOption is an option for NewMulti.
+type MultiOption func(*Multi)
+
+// PrecedenceOption sets the precedence to decide the overall code.
+// The default is DefaultPrecedence.
+func PrecedenceOption(p Precedence) MultiOption {
+	return func(m *Multi) {
+		m.precedence = p
+	}
+}
+
+// InternalDetailCodeOption sets the detail code of the Internal server errors
+// that the errors other than apperr.Err are added as (ex. the code for the internal server error
+// of the middlewares). The default is empty.
+func InternalDetailCodeOption(detailCode string) MultiOption {
+	return func(m *Multi) {
+		m.internalDetailCode = detailCode
+	}
+}
+
+// NewMulti creates new aggregate error builder.
+func NewMulti(opts ...MultiOption) *Multi {
+	m := &Multi{
+		precedence: DefaultPrecedence,
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// Add adds the errors to the aggregate error. nil errors are ignored.
+// The errors joined by errors.Join and the aggregate errors, including the wrapped ones, are flattened.
+// The errors other than apperr.Err are added as Internal server errors (see InternalDetailCodeOption).
+func (m *Multi) Add(errs ...error) *Multi {
+	for _, err := range errs {
+		m.add(err)
+	}
+	return m
+}
+
+func (m *Multi) add(err error) {
+	if err == nil {
+		return
+	}
+	if e, ok := err.(Err); ok {
+		m.addErr(e)
+		return
+	}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		m.Add(joined.Unwrap()...)
+		return
+	}
+	if e, ok := Extract(err); ok {
+		m.addErr(e)
+		return
+	}
+	m.errs = append(m.errs, WrapServerError(err, codes.Internal, m.internalDetailCode, "internal server error", ""))
+}
+
+// addErr adds the error, or the aggregated errors if it is an aggregate error.
+func (m *Multi) addErr(e Err) {
+	if me, ok := e.(MultiErr); ok {
+		m.errs = append(m.errs, me.Errors()...)
+		return
+	}
+	m.errs = append(m.errs, e)
+}
+
+// Len returns the number of the added errors.
+func (m *Multi) Len() int {
+	return len(m.errs)
+}
+
+// Err returns the aggregate error of the added errors.
+// It returns nil if no error has been added.
+func (m *Multi) Err() Err {
+	if len(m.errs) == 0 {
+		return nil
+	}
+	top := m.errs[0]
+	rank := len(m.precedence)
+	for _, e := range m.errs {
+		if i := slices.Index(m.precedence, e.Code()); i >= 0 && i < rank {
+			top, rank = e, i
+		}
+	}
+	return &multiError{
+		baseError: newBaseError(nil, top.Code(), top.DetailCode(), top.Message(), nil),
+		typ:       top.Type(),
+		errs:      slices.Clone(m.errs),
+	}
+}
+
+// Join returns the aggregate error of the errors with DefaultPrecedence.
+// It returns nil if all the errors are nil.
+// Use NewMulti with InternalDetailCodeOption to set the detail code of the errors other than apperr.Err.
+func Join(errs ...error) Err {
+	return NewMulti().Add(errs...).Err()
+}
+
+type multiError struct {
+	baseError
+	typ  Type
+	errs []Err
+}
+
+// Errors returns the aggregated errors.
+func (e *multiError) Errors() []Err {
+	return slices.Clone(e.errs)
+}
+
+// Unwrap returns the aggregated errors.
+func (e *multiError) Unwrap() []error {
+	errs := make([]error, 0, len(e.errs))
+	for _, err := range e.errs {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// Log returns the logs of the aggregated errors separated by newlines.
+func (e *multiError) Log() string {
+	var logs []string
+	for _, err := range e.errs {
+		if l := err.Log(); l != "" {
+			logs = append(logs, l)
+		}
+	}
+	return strings.Join(logs, "\n")
+}
+
+// Type returns error type.
+func (e *multiError) Type() Type {
+	return e.typ
+}
+
+// GRPCError returns gRPC error.
+// Each aggregated error is attached as a google.rpc.Status detail after the details of the overall error.
+// Use ToGRPCError to specify the options.
+func (e *multiError) GRPCError(domain string) error {
+	return e.grpcError(domain, nil)
+}
+
+func (e *multiError) grpcError(domain string, opts []GRPCOption) error {
+	st := e.grpcStatus(domain, opts)
+
+	items := make([]protoadapt.MessageV1, 0, len(e.errs))
+	for _, err := range e.errs {
+		if s, ok := status.FromError(ToGRPCError(err, domain, opts...)); ok {
+			items = append(items, s.Proto())
+		}
+	}
+	st, _ = st.WithDetails(items...)
+
+	return st.Err()
+}
+
+// MarshalJSON is a method to satisfy the json.Marshaler interface.
+// The aggregated errors are included as "errors".
+func (e *multiError) MarshalJSON() ([]byte, error) {
+	v := newErrorJSON(e)
+	for _, err := range e.errs {
+		b, merr := json.Marshal(err)
+		if merr != nil {
+			return nil, merr
+		}
+		v.Errors = append(v.Errors, b)
+	}
+	return json.Marshal(v)
+}
+
+var _ MultiErr = (*multiError)(nil)
@@ -0,0 +1,180 @@
+package apperr_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/takuoki/golib/apperr"
+	"google.golang.org/grpc/codes"
+)
+
+var (
+	multiNotFound = apperr.NewClientError(codes.NotFound, "E0001", "not found")
+	multiInvalid  = apperr.NewClientError(codes.InvalidArgument, "E0002", "invalid argument")
+	multiInternal = apperr.NewServerError(codes.Internal, "S0001", "internal server error", "db is down")
+)
+
+func TestMulti(t *testing.T) {
+	testcases := map[string]struct {
+		opts       []apperr.MultiOption
+		errs       []error
+		wantNil    bool
+		wantCode   codes.Code
+		wantDetail string
+		wantType   apperr.Type
+		wantLen    int
+		wantLog    string
+	}{
+		"client": {
+			errs:       []error{multiInvalid, multiNotFound},
+			wantCode:   codes.NotFound,
+			wantDetail: "E0001",
+			wantType:   apperr.ClientError,
+			wantLen:    2,
+		},
+		"server-first": {
+			errs:       []error{multiInvalid, multiInternal, multiNotFound},
+			wantCode:   codes.Internal,
+			wantDetail: "S0001",
+			wantType:   apperr.ServerError,
+			wantLen:    3,
+			wantLog:    "db is down",
+		},
+		"precedence": {
+			opts:       []apperr.MultiOption{apperr.PrecedenceOption(apperr.Precedence{codes.InvalidArgument})},
+			errs:       []error{multiInternal, multiNotFound, multiInvalid},
+			wantCode:   codes.InvalidArgument,
+			wantDetail: "E0002",
+			wantType:   apperr.ClientError,
+			wantLen:    3,
+			wantLog:    "db is down",
+		},
+		"not-in-precedence": {
+			opts:       []apperr.MultiOption{apperr.PrecedenceOption(apperr.Precedence{codes.Aborted})},
+			errs:       []error{multiNotFound, multiInvalid},
+			wantCode:   codes.NotFound,
+			wantDetail: "E0001",
+			wantType:   apperr.ClientError,
+			wantLen:    2,
+		},
+		"errors-join": {
+			errs:       []error{errors.Join(multiInvalid, fmt.Errorf("wrapped: %w", multiNotFound)), nil},
+			wantCode:   codes.NotFound,
+			wantDetail: "E0001",
+			wantType:   apperr.ClientError,
+			wantLen:    2,
+		},
+		"flatten": {
+			errs:       []error{apperr.Join(multiInvalid, multiNotFound), multiInvalid},
+			wantCode:   codes.NotFound,
+			wantDetail: "E0001",
+			wantType:   apperr.ClientError,
+			wantLen:    3,
+		},
+		"flatten-wrapped": {
+			errs:       []error{fmt.Errorf("wrapped: %w", apperr.Join(multiInvalid, multiNotFound)), multiInvalid},
+			wantCode:   codes.NotFound,
+			wantDetail: "E0001",
+			wantType:   apperr.ClientError,
+			wantLen:    3,
+		},
+		"not-apperr-with-detail-code": {
+			opts:       []apperr.MultiOption{apperr.InternalDetailCodeOption("S9999")},
+			errs:       []error{multiInvalid, errors.New("unexpected")},
+			wantCode:   codes.Internal,
+			wantDetail: "S9999",
+			wantType:   apperr.ServerError,
+			wantLen:    2,
+			wantLog:    "unexpected",
+		},
+		"not-apperr": {
+			errs:       []error{multiInvalid, errors.New("unexpected")},
+			wantCode:   codes.Internal,
+			wantDetail: "",
+			wantType:   apperr.ServerError,
+			wantLen:    2,
+			wantLog:    "unexpected",
+		},
+		"empty": {
+			errs:    []error{nil},
+			wantNil: true,
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			err := apperr.NewMulti(tc.opts...).Add(tc.errs...).Err()
+			if tc.wantNil {
+				assert.Nil(t, err, "Err is not nil.")
+				return
+			}
+			if !assert.NotNil(t, err, "Err is nil.") {
+				return
+			}
+			assert.Equal(t, tc.wantCode, err.Code(), "Code is not equal.")
+			assert.Equal(t, tc.wantDetail, err.DetailCode(), "DetailCode is not equal.")
+			assert.Equal(t, tc.wantType, err.Type(), "Type is not equal.")
+			assert.Equal(t, tc.wantLog, err.Log(), "Log is not equal.")
+			if me, ok := err.(apperr.MultiErr); assert.True(t, ok, "Err is not MultiErr.") {
+				assert.Len(t, me.Errors(), tc.wantLen)
+			}
+		})
+	}
+}
+
+func TestJoin(t *testing.T) {
+	err := apperr.Join(multiInvalid, multiNotFound)
+	assert.True(t, errors.Is(err, multiInvalid), "errors.Is must find the aggregated error.")
+	assert.True(t, errors.Is(err, multiNotFound), "errors.Is must find the aggregated error.")
+	assert.False(t, errors.Is(err, multiInternal), "errors.Is must not find the other error.")
+
+	e, ok := apperr.Extract(fmt.Errorf("wrapped: %w", err))
+	if assert.True(t, ok) {
+		assert.Equal(t, err, e, "The aggregate error must be extracted.")
+	}
+
+	assert.Nil(t, apperr.Join(), "Join of no errors must be nil.")
+}
+
+func TestMulti_GRPCError(t *testing.T) {
+	err := apperr.Join(multiInvalid, multiInternal)
+
+	e, ok := apperr.ExtractFromGRPCError(err.GRPCError("domain"))
+	if !assert.True(t, ok) {
+		return
+	}
+	assert.Equal(t, codes.Internal, e.Code(), "Code is not equal.")
+	assert.Equal(t, "S0001", e.DetailCode(), "DetailCode is not equal.")
+	assert.Equal(t, apperr.ServerError, e.Type(), "Type is not equal.")
+
+	me, ok := e.(apperr.MultiErr)
+	if assert.True(t, ok, "Err is not MultiErr.") && assert.Len(t, me.Errors(), 2) {
+		assert.True(t, errors.Is(me.Errors()[0], multiInvalid))
+		assert.True(t, errors.Is(me.Errors()[1], multiInternal))
+		assert.Equal(t, apperr.ServerError, me.Errors()[1].Type())
+	}
+}
+
+func TestMulti_JSON(t *testing.T) {
+	err := apperr.Join(multiInvalid, multiNotFound)
+
+	b, jerr := json.Marshal(err)
+	if !assert.NoError(t, jerr) {
+		return
+	}
+	assert.Equal(t, `{"type":"client-error","grpc_code":"NotFound","code":"E0001","message":"not found","errors":[`+
+		`{"type":"client-error","grpc_code":"InvalidArgument","code":"E0002","message":"invalid argument"},`+
+		`{"type":"client-error","grpc_code":"NotFound","code":"E0001","message":"not found"}]}`, string(b))
+
+	e, jerr := apperr.UnmarshalJSON(b)
+	if assert.NoError(t, jerr) {
+		me, ok := e.(apperr.MultiErr)
+		if assert.True(t, ok, "Err is not MultiErr.") {
+			assert.Len(t, me.Errors(), 2)
+			assert.True(t, errors.Is(e, multiInvalid))
+		}
+	}
+}
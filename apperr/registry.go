@@ -0,0 +1,209 @@
+package apperr
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc/codes"
+)
+
+// ErrDuplicateDetailCode is returned when the detail code has already been registered.
+var ErrDuplicateDetailCode = errors.New("duplicate detail code")
+
+// Definition is a definition of the error registered to the registry.
+type Definition struct {
+	DetailCode string
+	Code       codes.Code
+	Type       Type
+	// Message is the message returned to the client.
+	Message string
+	// Description is the explanation of the error for the API document.
+	Description string
+}
+
+// Registry is a registry of the errors that detects duplicate detail codes
+// and exports the registered errors as API documents.
+type Registry struct {
+	mu   sync.RWMutex
+	defs map[string]Definition
+}
+
+// NewRegistry creates new empty registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		defs: map[string]Definition{},
+	}
+}
+
+// Register registers the definition and returns the error created from it.
+// The server error has no log, wrap it with WrapServerError if necessary.
+// An error is returned if the definition is invalid or the detail code is already registered.
+func (r *Registry) Register(def Definition, opts ...Option) (Err, error) {
+	if def.DetailCode == "" {
+		return nil, errors.New("detail code is empty")
+	}
+	if def.Type != ClientError && def.Type != ServerError {
+		return nil, fmt.Errorf("invalid type of %s: %s", def.DetailCode, def.Type)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.defs[def.DetailCode]; ok {
+		return nil, fmt.Errorf("%w: %s", ErrDuplicateDetailCode, def.DetailCode)
+	}
+	r.defs[def.DetailCode] = def
+
+	if def.Type == ServerError {
+		return NewServerError(def.Code, def.DetailCode, def.Message, "", opts...), nil
+	}
+	return NewClientError(def.Code, def.DetailCode, def.Message, opts...), nil
+}
+
+// MustRegister is like Register but panics if the definition cannot be registered.
+// It simplifies the declaration of the errors as package variables.
+func (r *Registry) MustRegister(def Definition, opts ...Option) Err {
+	e, err := r.Register(def, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return e
+}
+
+// Lookup returns the definition of the detail code.
+func (r *Registry) Lookup(detailCode string) (Definition, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	def, ok := r.defs[detailCode]
+	return def, ok
+}
+
+// Definitions returns all the registered definitions in the order of the detail code.
+func (r *Registry) Definitions() []Definition {
+	r.mu.RLock()
+	defs := make([]Definition, 0, len(r.defs))
+	for _, def := range r.defs {
+		defs = append(defs, def)
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(defs, func(i, j int) bool {
+		return defs[i].DetailCode < defs[j].DetailCode
+	})
+	return defs
+}
+
+// WriteMarkdown writes the registered errors as a Markdown table.
+func (r *Registry) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("| Detail Code | gRPC Code | HTTP Status | Type | Message | Description |\n")
+	b.WriteString("| --- | --- | --- | --- | --- | --- |\n")
+	for _, def := range r.Definitions() {
+		fmt.Fprintf(&b, "| %s | %s | %d | %s | %s | %s |\n",
+			escapeMarkdown(def.DetailCode),
+			def.Code,
+			runtime.HTTPStatusFromCode(def.Code),
+			def.Type,
+			escapeMarkdown(def.Message),
+			escapeMarkdown(def.Description),
+		)
+	}
+
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return fmt.Errorf("failed to write markdown: %w", err)
+	}
+	return nil
+}
+
+type definitionJSON struct {
+	DetailCode  string `json:"detail_code"`
+	GRPCCode    string `json:"grpc_code"`
+	HTTPStatus  int    `json:"http_status"`
+	Type        string `json:"type"`
+	Message     string `json:"message"`
+	Description string `json:"description,omitempty"`
+}
+
+// WriteJSON writes the registered errors as a JSON array.
+func (r *Registry) WriteJSON(w io.Writer) error {
+	defs := r.Definitions()
+	v := make([]definitionJSON, 0, len(defs))
+	for _, def := range defs {
+		v = append(v, definitionJSON{
+			DetailCode:  def.DetailCode,
+			GRPCCode:    def.Code.String(),
+			HTTPStatus:  runtime.HTTPStatusFromCode(def.Code),
+			Type:        def.Type.String(),
+			Message:     def.Message,
+			Description: def.Description,
+		})
+	}
+	return writeJSON(w, v)
+}
+
+// WriteOpenAPI writes the registered errors as OpenAPI components in JSON.
+// It contains the "Error" schema of the error response, whose code is enumerated,
+// and a response for each detail code that refers to the schema.
+func (r *Registry) WriteOpenAPI(w io.Writer) error {
+	type object = map[string]any
+
+	defs := r.Definitions()
+	enum := make([]string, 0, len(defs))
+	responses := object{}
+	for _, def := range defs {
+		enum = append(enum, def.DetailCode)
+		description := def.Message
+		if def.Description != "" {
+			description = def.Description
+		}
+		responses[def.DetailCode] = object{
+			"description": description,
+			"content": object{
+				"application/json": object{
+					"schema": object{"$ref": "#/components/schemas/Error"},
+					"example": object{
+						"code":    def.DetailCode,
+						"message": def.Message,
+					},
+				},
+			},
+		}
+	}
+
+	return writeJSON(w, object{
+		"components": object{
+			"schemas": object{
+				"Error": object{
+					"type":     "object",
+					"required": []string{"code", "message"},
+					"properties": object{
+						"code":    object{"type": "string", "enum": enum},
+						"message": object{"type": "string"},
+					},
+				},
+			},
+			"responses": responses,
+		},
+	})
+}
+
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return fmt.Errorf("failed to write json: %w", err)
+	}
+	return nil
+}
+
+func escapeMarkdown(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", "<br>")
+}
@@ -0,0 +1,137 @@
+package apperr_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/takuoki/golib/apperr"
+	"google.golang.org/grpc/codes"
+)
+
+func newTestRegistry(t *testing.T) *apperr.Registry {
+	t.Helper()
+	r := apperr.NewRegistry()
+	r.MustRegister(apperr.Definition{
+		DetailCode:  "S0001",
+		Code:        codes.Internal,
+		Type:        apperr.ServerError,
+		Message:     "internal server error",
+		Description: "An unexpected error occurred.",
+	})
+	r.MustRegister(apperr.Definition{
+		DetailCode:  "E0001",
+		Code:        codes.NotFound,
+		Type:        apperr.ClientError,
+		Message:     "not found",
+		Description: "The resource | entity does not exist.",
+	})
+	return r
+}
+
+func TestRegistry_Register(t *testing.T) {
+	testcases := map[string]struct {
+		def     apperr.Definition
+		wantErr string
+	}{
+		"client": {
+			def: apperr.Definition{DetailCode: "E0002", Code: codes.InvalidArgument, Type: apperr.ClientError, Message: "invalid"},
+		},
+		"server": {
+			def: apperr.Definition{DetailCode: "S0002", Code: codes.Unavailable, Type: apperr.ServerError, Message: "unavailable"},
+		},
+		"duplicate": {
+			def:     apperr.Definition{DetailCode: "E0001", Code: codes.InvalidArgument, Type: apperr.ClientError, Message: "invalid"},
+			wantErr: "duplicate detail code: E0001",
+		},
+		"empty-detail-code": {
+			def:     apperr.Definition{Code: codes.InvalidArgument, Type: apperr.ClientError},
+			wantErr: "detail code is empty",
+		},
+		"invalid-type": {
+			def:     apperr.Definition{DetailCode: "E0003", Code: codes.InvalidArgument},
+			wantErr: "invalid type of E0003: unknown",
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			r := newTestRegistry(t)
+			e, err := r.Register(tc.def)
+			if tc.wantErr != "" {
+				if assert.Error(t, err) {
+					assert.Equal(t, tc.wantErr, err.Error())
+				}
+				return
+			}
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, tc.def.DetailCode, e.DetailCode(), "DetailCode is not equal.")
+			assert.Equal(t, tc.def.Code, e.Code(), "Code is not equal.")
+			assert.Equal(t, tc.def.Type, e.Type(), "Type is not equal.")
+			assert.Equal(t, tc.def.Message, e.Message(), "Message is not equal.")
+
+			def, ok := r.Lookup(tc.def.DetailCode)
+			assert.True(t, ok, "Lookup is not ok.")
+			assert.Equal(t, tc.def, def, "Definition is not equal.")
+		})
+	}
+
+	t.Run("errors-is", func(t *testing.T) {
+		_, err := newTestRegistry(t).Register(apperr.Definition{DetailCode: "E0001", Type: apperr.ClientError})
+		assert.True(t, errors.Is(err, apperr.ErrDuplicateDetailCode))
+	})
+	t.Run("must-register-panics", func(t *testing.T) {
+		r := newTestRegistry(t)
+		assert.Panics(t, func() {
+			r.MustRegister(apperr.Definition{DetailCode: "E0001", Type: apperr.ClientError})
+		})
+	})
+}
+
+func TestRegistry_Definitions(t *testing.T) {
+	defs := newTestRegistry(t).Definitions()
+	if assert.Len(t, defs, 2) {
+		assert.Equal(t, "E0001", defs[0].DetailCode)
+		assert.Equal(t, "S0001", defs[1].DetailCode)
+	}
+}
+
+func TestRegistry_WriteMarkdown(t *testing.T) {
+	buf := &bytes.Buffer{}
+	assert.NoError(t, newTestRegistry(t).WriteMarkdown(buf))
+	assert.Equal(t, ""+
+		"| Detail Code | gRPC Code | HTTP Status | Type | Message | Description |\n"+
+		"| --- | --- | --- | --- | --- | --- |\n"+
+		"| E0001 | NotFound | 404 | client-error | not found | The resource \\| entity does not exist. |\n"+
+		"| S0001 | Internal | 500 | server-error | internal server error | An unexpected error occurred. |\n",
+		buf.String())
+}
+
+func TestRegistry_WriteJSON(t *testing.T) {
+	buf := &bytes.Buffer{}
+	assert.NoError(t, newTestRegistry(t).WriteJSON(buf))
+	assert.JSONEq(t, `[
+		{"detail_code":"E0001","grpc_code":"NotFound","http_status":404,"type":"client-error","message":"not found","description":"The resource | entity does not exist."},
+		{"detail_code":"S0001","grpc_code":"Internal","http_status":500,"type":"server-error","message":"internal server error","description":"An unexpected error occurred."}
+	]`, buf.String())
+}
+
+func TestRegistry_WriteOpenAPI(t *testing.T) {
+	buf := &bytes.Buffer{}
+	assert.NoError(t, newTestRegistry(t).WriteOpenAPI(buf))
+	assert.JSONEq(t, `{"components":{
+		"schemas":{"Error":{"type":"object","required":["code","message"],"properties":{
+			"code":{"type":"string","enum":["E0001","S0001"]},
+			"message":{"type":"string"}
+		}}},
+		"responses":{
+			"E0001":{"description":"The resource | entity does not exist.","content":{"application/json":{
+				"schema":{"$ref":"#/components/schemas/Error"},"example":{"code":"E0001","message":"not found"}}}},
+			"S0001":{"description":"An unexpected error occurred.","content":{"application/json":{
+				"schema":{"$ref":"#/components/schemas/Error"},"example":{"code":"S0001","message":"internal server error"}}}}
+		}
+	}}`, buf.String())
+}
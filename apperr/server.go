@@ -1,53 +1,42 @@
 package apperr
 
 import (
-	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
-	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
 )
 
 type serverError struct {
-	code       codes.Code
-	detailCode string
-	message    string
-	log        string
+	baseError
+	log string
 }
 
 // NewServerError creates new server error.
 // Set the gRPC code to `code`.
 // Set the error detail code (ex. "S0001") that the client can handle to `detailCode`.
-func NewServerError(code codes.Code, detailCode, message, log string) Err {
+// The error details can be attached with `opts` (ex. RetryDelayOption).
+func NewServerError(code codes.Code, detailCode, message, log string, opts ...Option) Err {
 	return &serverError{
-		code:       code,
-		detailCode: detailCode,
-		message:    message,
-		log:        log,
+		baseError: newBaseError(nil, code, detailCode, message, opts),
+		log:       log,
 	}
 }
 
-// Error is a method to satisfy the error interface.
-func (e *serverError) Error() string {
-	return e.message
-}
-
-// Code returns code value.
-func (e *serverError) Code() codes.Code {
-	return e.code
-}
-
-// DetailCode returns detail code string.
-func (e *serverError) DetailCode() string {
-	return e.detailCode
-}
-
-// Message returns message string.
-func (e *serverError) Message() string {
-	return e.message
+// WrapServerError creates new server error caused by `cause`.
+// The cause can be retrieved with errors.Unwrap, errors.Is and errors.As.
+// If `log` is empty, the message of the cause (including its wrapped errors) is used as the log.
+// The other arguments are the same as NewServerError.
+func WrapServerError(cause error, code codes.Code, detailCode, message, log string, opts ...Option) Err {
+	return &serverError{
+		baseError: newBaseError(cause, code, detailCode, message, opts),
+		log:       log,
+	}
 }
 
 // Log returns log string.
+// If the log is not specified, it returns the message of the cause.
 func (e *serverError) Log() string {
+	if e.log == "" && e.cause != nil {
+		return e.cause.Error()
+	}
 	return e.log
 }
 
@@ -55,19 +44,3 @@ func (e *serverError) Log() string {
 func (e *serverError) Type() Type {
 	return ServerError
 }
-
-// HTTPStatus returns HTTP status code.
-func (e *serverError) HTTPStatus() int {
-	return runtime.HTTPStatusFromCode(e.code)
-}
-
-// GRPCError returns gRPC error.
-func (e *serverError) GRPCError(domain string) error {
-	st := status.New(e.Code(), e.Message())
-	st, _ = st.WithDetails(&errdetails.ErrorInfo{
-		Reason: e.DetailCode(),
-		Domain: domain,
-	})
-
-	return st.Err()
-}
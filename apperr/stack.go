@@ -0,0 +1,56 @@
+package apperr
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+const maxStackDepth = 32
+
+// StackTrace is a call stack captured at creation of the error.
+type StackTrace []uintptr
+
+// StackTraceOption captures the call stack at creation of the error.
+// The stack can be retrieved with StackTrace or printed with the verb %+v.
+func StackTraceOption() Option {
+	return func(e *baseError) {
+		// The stack is captured after all the options are applied, see newBaseError.
+		e.stack = StackTrace{}
+	}
+}
+
+// Frames returns the frames of the call stack.
+func (s StackTrace) Frames() []runtime.Frame {
+	if len(s) == 0 {
+		return nil
+	}
+	frames := runtime.CallersFrames(s)
+	var result []runtime.Frame
+	for {
+		f, more := frames.Next()
+		result = append(result, f)
+		if !more {
+			break
+		}
+	}
+	return result
+}
+
+// String returns the function, file and line of each frame.
+//
+//	main.handler
+//		/path/to/main.go:12
+func (s StackTrace) String() string {
+	var b strings.Builder
+	for _, f := range s.Frames() {
+		fmt.Fprintf(&b, "%s\n\t%s:%d\n", f.Function, f.File, f.Line)
+	}
+	return b.String()
+}
+
+func callers(skip int) StackTrace {
+	pcs := make([]uintptr, maxStackDepth)
+	n := runtime.Callers(skip+1, pcs)
+	return pcs[:n]
+}
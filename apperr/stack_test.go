@@ -0,0 +1,54 @@
+package apperr_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/takuoki/golib/apperr"
+	"google.golang.org/grpc/codes"
+)
+
+func TestStackTrace(t *testing.T) {
+	t.Run("server", func(t *testing.T) {
+		err := apperr.NewServerError(codes.Internal, "code", "message", "log", apperr.StackTraceOption())
+		frames := err.(apperr.StackTracer).StackTrace().Frames()
+		if assert.NotEmpty(t, frames, "StackTrace is empty.") {
+			assert.Equal(t, "github.com/takuoki/golib/apperr_test.TestStackTrace.func1", frames[0].Function,
+				"The first frame must be the caller of the constructor.")
+		}
+	})
+	t.Run("wrap", func(t *testing.T) {
+		err := apperr.WrapServerError(errors.New("cause"), codes.Internal, "code", "message", "", apperr.StackTraceOption())
+		frames := err.(apperr.StackTracer).StackTrace().Frames()
+		if assert.NotEmpty(t, frames, "StackTrace is empty.") {
+			assert.Equal(t, "github.com/takuoki/golib/apperr_test.TestStackTrace.func2", frames[0].Function,
+				"The first frame must be the caller of the constructor.")
+		}
+	})
+	t.Run("no-option", func(t *testing.T) {
+		err := apperr.NewServerError(codes.Internal, "code", "message", "log")
+		assert.Nil(t, err.(apperr.StackTracer).StackTrace(), "StackTrace is not nil.")
+		assert.Nil(t, err.(apperr.StackTracer).StackTrace().Frames(), "Frames is not nil.")
+	})
+}
+
+func TestFormat(t *testing.T) {
+	withStack := apperr.NewClientError(codes.NotFound, "code", "message", apperr.StackTraceOption())
+	noStack := apperr.NewClientError(codes.NotFound, "code", "message")
+
+	assert.Equal(t, "message", fmt.Sprintf("%v", withStack))
+	assert.Equal(t, "message", fmt.Sprintf("%s", withStack))
+	assert.Equal(t, `"message"`, fmt.Sprintf("%q", withStack))
+	assert.Equal(t, "message", fmt.Sprintf("%+v", noStack))
+	assert.Equal(t, "6d657373616765", fmt.Sprintf("%x", withStack))
+	assert.Equal(t, "  message", fmt.Sprintf("%9s", withStack))
+	assert.Equal(t, "mes", fmt.Sprintf("%.3s", noStack))
+	assert.Equal(t, "message  ", fmt.Sprintf("%-9v", noStack))
+
+	s := fmt.Sprintf("%+v", withStack)
+	assert.True(t, strings.HasPrefix(s, "message\ngithub.com/takuoki/golib/apperr_test.TestFormat\n\t"), "%+v must print the stack trace: %s", s)
+	assert.Contains(t, s, "stack_test.go:")
+}
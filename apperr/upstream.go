@@ -0,0 +1,53 @@
+package apperr
+
+import (
+	"strings"
+)
+
+// UpstreamMetadataKey is the key of errdetails.ErrorInfo metadata that holds
+// the upstream domains separated by commas when UpstreamChainOption is specified.
+// The key is reserved by this package and namespaced not to collide with application metadata,
+// so do not use it in MetadataOption.
+const UpstreamMetadataKey = "apperr.upstream"
+
+type grpcOptions struct {
+	upstreamChain bool
+}
+
+// GRPCOption is an option when converting the error to a gRPC error.
+type GRPCOption func(*grpcOptions)
+
+// UpstreamChainOption includes the upstream domains (see Upstream) in the gRPC error
+// converted by ToGRPCError, so that the downstream service can tell which service actually failed.
+func UpstreamChainOption() GRPCOption {
+	return func(o *grpcOptions) {
+		o.upstreamChain = true
+	}
+}
+
+// originOption sets the domain and the upstream of the error extracted from a gRPC error.
+func originOption(domain string, upstream []string) Option {
+	return func(e *baseError) {
+		e.domain = domain
+		e.upstream = upstream
+	}
+}
+
+// parseUpstream returns the upstream domains ending with `domain`,
+// and removes the upstream domains from the metadata.
+func parseUpstream(domain string, d *Details) []string {
+	var upstream []string
+	if v, ok := d.Metadata[UpstreamMetadataKey]; ok {
+		delete(d.Metadata, UpstreamMetadataKey)
+		if len(d.Metadata) == 0 {
+			d.Metadata = nil
+		}
+		if v != "" {
+			upstream = strings.Split(v, ",")
+		}
+	}
+	if domain != "" {
+		upstream = append(upstream, domain)
+	}
+	return upstream
+}
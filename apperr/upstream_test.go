@@ -0,0 +1,69 @@
+package apperr_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/takuoki/golib/apperr"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestUpstream(t *testing.T) {
+	// The error is raised by service C, and passes through service B to service A.
+	origin := apperr.NewClientError(codes.NotFound, "E0001", "not found",
+		apperr.MetadataOption(map[string]string{"id": "1"}))
+	assert.Equal(t, "", origin.(apperr.Originator).Domain(), "Domain of the local error must be empty.")
+	assert.Nil(t, origin.(apperr.Originator).Upstream(), "Upstream of the local error must be nil.")
+
+	inB, ok := apperr.ExtractFromGRPCError(origin.GRPCError("c.example.com"))
+	if !assert.True(t, ok) {
+		return
+	}
+	assert.Equal(t, "c.example.com", inB.(apperr.Originator).Domain(), "Domain is not equal.")
+	assert.Equal(t, []string{"c.example.com"}, inB.(apperr.Originator).Upstream(), "Upstream is not equal.")
+	assert.Equal(t, map[string]string{"id": "1"}, inB.(apperr.Detailer).Details().Metadata, "Metadata is not equal.")
+
+	t.Run("with-chain", func(t *testing.T) {
+		inA, ok := apperr.ExtractFromGRPCError(apperr.ToGRPCError(inB, "b.example.com", apperr.UpstreamChainOption()))
+		if !assert.True(t, ok) {
+			return
+		}
+		assert.Equal(t, "b.example.com", inA.(apperr.Originator).Domain(), "Domain is not equal.")
+		assert.Equal(t, []string{"c.example.com", "b.example.com"}, inA.(apperr.Originator).Upstream(), "Upstream is not equal.")
+		assert.Equal(t, map[string]string{"id": "1"}, inA.(apperr.Detailer).Details().Metadata, "The upstream must not be left in metadata.")
+
+		inZ, ok := apperr.ExtractFromGRPCError(apperr.ToGRPCError(inA, "a.example.com", apperr.UpstreamChainOption()))
+		if assert.True(t, ok) {
+			assert.Equal(t, []string{"c.example.com", "b.example.com", "a.example.com"}, inZ.(apperr.Originator).Upstream(), "Upstream is not equal.")
+		}
+	})
+	t.Run("without-chain", func(t *testing.T) {
+		inA, ok := apperr.ExtractFromGRPCError(inB.GRPCError("b.example.com"))
+		if !assert.True(t, ok) {
+			return
+		}
+		assert.Equal(t, "b.example.com", inA.(apperr.Originator).Domain(), "Domain is not equal.")
+		assert.Equal(t, []string{"b.example.com"}, inA.(apperr.Originator).Upstream(), "Upstream is not equal.")
+	})
+	t.Run("metadata", func(t *testing.T) {
+		st, _ := status.FromError(apperr.ToGRPCError(inB, "b.example.com", apperr.UpstreamChainOption()))
+		info, ok := st.Details()[0].(*errdetails.ErrorInfo)
+		if assert.True(t, ok) {
+			assert.Equal(t, "b.example.com", info.GetDomain())
+			assert.Equal(t, map[string]string{"id": "1", apperr.UpstreamMetadataKey: "c.example.com"}, info.GetMetadata())
+		}
+		assert.Equal(t, map[string]string{"id": "1"}, inB.(apperr.Detailer).Details().Metadata, "The original metadata must not be changed.")
+	})
+	t.Run("application-metadata", func(t *testing.T) {
+		e := apperr.NewClientError(codes.NotFound, "E0001", "not found",
+			apperr.MetadataOption(map[string]string{"upstream": "x"}))
+
+		in, ok := apperr.ExtractFromGRPCError(e.GRPCError("b.example.com"))
+		if assert.True(t, ok) {
+			assert.Equal(t, map[string]string{"upstream": "x"}, in.(apperr.Detailer).Details().Metadata, "The application metadata must be kept.")
+			assert.Equal(t, []string{"b.example.com"}, in.(apperr.Originator).Upstream(), "Upstream is not equal.")
+		}
+	})
+}
@@ -0,0 +1,43 @@
+package apperr
+
+import (
+	"google.golang.org/grpc/codes"
+)
+
+// Validation is a builder of the validation error that reports multiple invalid fields at once.
+// The zero value cannot be used, create it with NewValidation.
+type Validation struct {
+	detailCode string
+	message    string
+	opts       []Option
+}
+
+// NewValidation creates new validation error builder.
+// `detailCode` and `message` are those of the whole validation error (ex. "E0100", "invalid request").
+func NewValidation(detailCode, message string) *Validation {
+	return &Validation{
+		detailCode: detailCode,
+		message:    message,
+	}
+}
+
+// AddFieldViolation adds a violation of the field.
+// Set the error detail code of the violation (ex. "E0101") to `detailCode`.
+func (v *Validation) AddFieldViolation(field, detailCode, message string) *Validation {
+	v.opts = append(v.opts, FieldViolationOption(field, detailCode, message))
+	return v
+}
+
+// HasViolations returns whether any violation has been added.
+func (v *Validation) HasViolations() bool {
+	return len(v.opts) > 0
+}
+
+// Err returns the validation error as an InvalidArgument client error with the field violations.
+// It returns nil if no violation has been added.
+func (v *Validation) Err() Err {
+	if !v.HasViolations() {
+		return nil
+	}
+	return NewClientError(codes.InvalidArgument, v.detailCode, v.message, v.opts...)
+}
@@ -0,0 +1,57 @@
+package apperr_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/takuoki/golib/apperr"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestValidation(t *testing.T) {
+	t.Run("violations", func(t *testing.T) {
+		v := apperr.NewValidation("E0100", "invalid request").
+			AddFieldViolation("name", "E0101", "name is required").
+			AddFieldViolation("age", "E0102", "age must be positive")
+
+		assert.True(t, v.HasViolations(), "HasViolations is not true.")
+		err := v.Err()
+		if !assert.NotNil(t, err, "Err is nil.") {
+			return
+		}
+		assert.Equal(t, codes.InvalidArgument, err.Code(), "Code is not equal.")
+		assert.Equal(t, "E0100", err.DetailCode(), "DetailCode is not equal.")
+		assert.Equal(t, "invalid request", err.Message(), "Message is not equal.")
+		assert.Equal(t, apperr.ClientError, err.Type(), "Type is not equal.")
+		assert.Equal(t, []apperr.FieldViolation{
+			{Field: "name", Reason: "E0101", Description: "name is required"},
+			{Field: "age", Reason: "E0102", Description: "age must be positive"},
+		}, err.(apperr.Detailer).Details().FieldViolations, "FieldViolations is not equal.")
+	})
+	t.Run("no-violations", func(t *testing.T) {
+		v := apperr.NewValidation("E0100", "invalid request")
+		assert.False(t, v.HasViolations(), "HasViolations is not false.")
+		assert.Nil(t, v.Err(), "Err is not nil.")
+	})
+	t.Run("grpc", func(t *testing.T) {
+		err := apperr.NewValidation("E0100", "invalid request").
+			AddFieldViolation("name", "E0101", "name is required").
+			Err()
+
+		st, _ := status.FromError(err.GRPCError("domain"))
+		var br *errdetails.BadRequest
+		for _, d := range st.Details() {
+			if v, ok := d.(*errdetails.BadRequest); ok {
+				br = v
+			}
+		}
+		if assert.NotNil(t, br, "BadRequest is not attached.") && assert.Len(t, br.GetFieldViolations(), 1) {
+			fv := br.GetFieldViolations()[0]
+			assert.Equal(t, "name", fv.GetField(), "Field is not equal.")
+			assert.Equal(t, "E0101", fv.GetReason(), "Reason is not equal.")
+			assert.Equal(t, "name is required", fv.GetDescription(), "Description is not equal.")
+		}
+	})
+}
@@ -22,29 +22,32 @@ type basicLogger struct {
 // NewBasicLogger creates a basic logger that outputs in JSON format.
 // It handles the necessity of output according to the log level,
 // and outputs context information to the log in common.
-func NewBasicLogger(w io.Writer, opts ...Option) Logger {
+// If an invalid option is specified, an error will be returned.
+func NewBasicLogger(w io.Writer, opts ...Option) (Logger, error) {
 	logger := &basicLogger{
 		out:        w,
 		timeFormat: time.RFC3339,
 	}
-	for _, opt := range opts {
-		// basicLogger option never returns an error
-		_ = opt(logger)
+	if err := ApplyOptions(logger, opts...); err != nil {
+		return nil, err
 	}
-	return logger
+	return logger, nil
 }
 
-func (l *basicLogger) setLevel(lv Level) error {
+// SetLevel is a method to satisfy the Configurable interface.
+func (l *basicLogger) SetLevel(lv Level) error {
 	l.level = lv
 	return nil
 }
 
-func (l *basicLogger) setTimeFormat(format string) error {
+// SetTimeFormat is a method to satisfy the Configurable interface.
+func (l *basicLogger) SetTimeFormat(format string) error {
 	l.timeFormat = format
 	return nil
 }
 
-func (l *basicLogger) setImageTag(tag string) error {
+// SetImageTag is a method to satisfy the Configurable interface.
+func (l *basicLogger) SetImageTag(tag string) error {
 	l.imageTag = tag
 	return nil
 }
@@ -106,7 +109,7 @@ func (l *basicLogger) Print(ctx context.Context, lv Level, msg string, labels ma
 		return
 	}
 	log := basicLog{
-		Time:      time.Now().Format(l.timeFormat),
+		Time:      now().Format(l.timeFormat),
 		Level:     lv.String(),
 		Message:   msg,
 		ImageTag:  l.imageTag,
@@ -81,7 +81,10 @@ func TestBasicLoggerPrint(t *testing.T) {
 			}
 
 			buf := &bytes.Buffer{}
-			logger := applog.NewBasicLogger(buf, opts...)
+			logger, err := applog.NewBasicLogger(buf, opts...)
+			if err != nil {
+				t.Fatalf("error occurred in NewBasicLogger: %v", err)
+			}
 
 			ctx := context.Background()
 			if c.requestID != "" {
@@ -96,10 +99,14 @@ func TestBasicLoggerPrint(t *testing.T) {
 
 func TestBasicLoggerLevel(t *testing.T) {
 	newLogger := func(w io.Writer) applog.Logger {
-		return applog.NewBasicLogger(
+		l, err := applog.NewBasicLogger(
 			w,
 			applog.TimeFormatOption("15:04:05"),
-			applog.LevelOption(applog.UnknownLevel))
+			applog.LevelOption(applog.TraceLevel))
+		if err != nil {
+			t.Fatalf("error occurred in NewBasicLogger: %v", err)
+		}
+		return l
 	}
 	t.Run("critical", func(t *testing.T) {
 		buf := &bytes.Buffer{}
@@ -162,3 +169,20 @@ func TestBasicLoggerLevel(t *testing.T) {
 		assert.Regexp(t, `^{"time":"\d{2}:\d{2}:\d{2}","level":"TRACE","message":"value: abc"}`+"\n$", buf.String())
 	})
 }
+
+func TestBasicLoggerOptionError(t *testing.T) {
+	t.Run("level", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		_, err := applog.NewBasicLogger(buf, applog.LevelOption(applog.UnknownLevel))
+		if assert.NotNil(t, err) {
+			assert.Equal(t, "invalid log level: -3", err.Error())
+		}
+	})
+	t.Run("timeFormat", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		_, err := applog.NewBasicLogger(buf, applog.TimeFormatOption("YYYY-MM-DD HH:mm:ss"))
+		if assert.NotNil(t, err) {
+			assert.Equal(t, `invalid time format "YYYY-MM-DD HH:mm:ss": no layout element found`, err.Error())
+		}
+	})
+}
@@ -0,0 +1,142 @@
+package applog
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Format is the output format of the logger.
+type Format string
+
+// Format list. Default is BasicFormat.
+const (
+	BasicFormat       Format = "basic"
+	GoogleCloudFormat Format = "googlecloud"
+	SimpleFormat      Format = "simple"
+)
+
+// Output destinations other than a file path.
+const (
+	StdoutOutput = "stdout"
+	StderrOutput = "stderr"
+)
+
+// Environment variable names read by NewFromEnv (without prefix).
+const (
+	EnvFormat     = "LOG_FORMAT"
+	EnvLevel      = "LOG_LEVEL"
+	EnvTimeFormat = "LOG_TIME_FORMAT"
+	EnvImageTag   = "LOG_IMAGE_TAG"
+	EnvOutput     = "LOG_OUTPUT"
+)
+
+// Config is a configuration for creating a logger with NewFromConfig.
+// The zero value creates a basic logger that outputs INFO or higher to stdout.
+type Config struct {
+	// Format is the output format. The default is BasicFormat.
+	Format Format
+	// Level is the log level that the logger outputs. The default is InfoLevel.
+	Level Level
+	// TimeFormat is the time format that the logger outputs.
+	// The default depends on the format. SimpleFormat does not accept it.
+	TimeFormat string
+	// ImageTag is the image tag that the logger outputs.
+	// SimpleFormat does not accept it.
+	ImageTag string
+	// Output is the output destination.
+	// Specify "stdout", "stderr" or a file path. The default is "stdout".
+	// A file is opened in append mode and stays open for the lifetime of the process.
+	Output string
+}
+
+// NewFromConfig creates a logger according to the configuration.
+// If the configuration is invalid, an error describing the invalid item will be returned.
+func NewFromConfig(cfg Config) (Logger, error) {
+	format, err := parseFormat(string(cfg.Format))
+	if err != nil {
+		return nil, err
+	}
+	opts := []Option{LevelOption(cfg.Level)}
+	if cfg.TimeFormat != "" {
+		opts = append(opts, TimeFormatOption(cfg.TimeFormat))
+	}
+	if cfg.ImageTag != "" {
+		opts = append(opts, ImageTagOption(cfg.ImageTag))
+	}
+
+	w, err := openOutput(cfg.Output)
+	if err != nil {
+		return nil, err
+	}
+
+	var logger Logger
+	switch format {
+	case GoogleCloudFormat:
+		logger, err = NewGoogleCloudLogger(w, opts...)
+	case SimpleFormat:
+		logger, err = NewSimpleLogger(w, opts...)
+	default:
+		logger, err = NewBasicLogger(w, opts...)
+	}
+	if err != nil {
+		if f, ok := w.(*os.File); ok && f != os.Stdout && f != os.Stderr {
+			_ = f.Close()
+		}
+		return nil, err
+	}
+	return logger, nil
+}
+
+// NewFromEnv creates a logger according to the environment variables.
+// The variable names are `prefix` followed by LOG_FORMAT, LOG_LEVEL, LOG_TIME_FORMAT,
+// LOG_IMAGE_TAG and LOG_OUTPUT (ex. "APP_LOG_LEVEL" when `prefix` is "APP_").
+// Unset variables fall back to the defaults of Config.
+func NewFromEnv(prefix string) (Logger, error) {
+	cfg := Config{
+		Format:     Format(os.Getenv(prefix + EnvFormat)),
+		TimeFormat: os.Getenv(prefix + EnvTimeFormat),
+		ImageTag:   os.Getenv(prefix + EnvImageTag),
+		Output:     os.Getenv(prefix + EnvOutput),
+	}
+	if lv := os.Getenv(prefix + EnvLevel); lv != "" {
+		l, err := ParseLevel(lv)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s%s %q: %w", prefix, EnvLevel, lv, err)
+		}
+		cfg.Level = l
+	}
+
+	logger, err := NewFromConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create logger from environment variables: %w", err)
+	}
+	return logger, nil
+}
+
+func parseFormat(f string) (Format, error) {
+	switch Format(strings.ToLower(f)) {
+	case "", BasicFormat:
+		return BasicFormat, nil
+	case GoogleCloudFormat:
+		return GoogleCloudFormat, nil
+	case SimpleFormat:
+		return SimpleFormat, nil
+	}
+	return "", fmt.Errorf("invalid log format: %q", f)
+}
+
+func openOutput(output string) (io.Writer, error) {
+	switch strings.ToLower(output) {
+	case "", StdoutOutput:
+		return os.Stdout, nil
+	case StderrOutput:
+		return os.Stderr, nil
+	}
+	f, err := os.OpenFile(output, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open log output file: %w", err)
+	}
+	return f, nil
+}
@@ -0,0 +1,135 @@
+package applog_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/takuoki/golib/applog"
+)
+
+func TestNewFromConfig(t *testing.T) {
+	testcase := map[string]struct {
+		cfg     applog.Config
+		want    string
+		wantErr string
+	}{
+		"default": {
+			cfg:  applog.Config{TimeFormat: "15:04:05"},
+			want: `^{"time":"\d{2}:\d{2}:\d{2}","level":"WARN","message":"message"}` + "\n$",
+		},
+		"basic": {
+			cfg: applog.Config{
+				Format:     applog.BasicFormat,
+				Level:      applog.WarnLevel,
+				TimeFormat: "15:04:05",
+				ImageTag:   "v1.0.0",
+			},
+			want: `^{"time":"\d{2}:\d{2}:\d{2}","level":"WARN","message":"message","image_tag":"v1.0.0"}` + "\n$",
+		},
+		"googlecloud": {
+			cfg: applog.Config{
+				Format:     applog.GoogleCloudFormat,
+				TimeFormat: "15:04:05",
+				ImageTag:   "v1.0.0",
+			},
+			want: `^{"timestamp":"\d{2}:\d{2}:\d{2}","severity":"WARNING","message":"message","labels":{"image_tag":"v1.0.0"}}` + "\n$",
+		},
+		"simple": {
+			cfg:  applog.Config{Format: "SIMPLE"},
+			want: "^message\n$",
+		},
+		"level": {
+			cfg:  applog.Config{Format: applog.SimpleFormat, Level: applog.ErrorLevel},
+			want: "^$",
+		},
+		"invalid-format": {
+			cfg:     applog.Config{Format: "text"},
+			wantErr: `invalid log format: "text"`,
+		},
+		"invalid-level": {
+			cfg:     applog.Config{Level: applog.UnknownLevel},
+			wantErr: "invalid log level: -3",
+		},
+		"invalid-time-format": {
+			cfg:     applog.Config{TimeFormat: "YYYY-MM-DD"},
+			wantErr: `invalid time format "YYYY-MM-DD": no layout element found`,
+		},
+		"simple-with-time-format": {
+			cfg:     applog.Config{Format: applog.SimpleFormat, TimeFormat: "15:04:05"},
+			wantErr: "TimeFormatOption is not available for simpleLogger",
+		},
+		"invalid-output": {
+			cfg:     applog.Config{Output: filepath.Join(t.TempDir(), "not-exist", "app.log")},
+			wantErr: "failed to open log output file: .*",
+		},
+	}
+
+	for name, c := range testcase {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "app.log")
+			if c.cfg.Output == "" {
+				c.cfg.Output = path
+			}
+
+			logger, err := applog.NewFromConfig(c.cfg)
+			if c.wantErr != "" {
+				if assert.NotNil(t, err) {
+					assert.Regexp(t, "^"+c.wantErr+"$", err.Error())
+				}
+				return
+			}
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			logger.Warn(context.Background(), "message")
+
+			b, err := os.ReadFile(path)
+			if assert.Nil(t, err) {
+				assert.Regexp(t, c.want, string(b))
+			}
+		})
+	}
+}
+
+func TestNewFromEnv(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "app.log")
+		t.Setenv("APP_LOG_FORMAT", "basic")
+		t.Setenv("APP_LOG_LEVEL", "ERROR")
+		t.Setenv("APP_LOG_TIME_FORMAT", "15:04:05")
+		t.Setenv("APP_LOG_IMAGE_TAG", "v1.0.0")
+		t.Setenv("APP_LOG_OUTPUT", path)
+
+		logger, err := applog.NewFromEnv("APP_")
+		if !assert.Nil(t, err) {
+			return
+		}
+		logger.Warn(context.Background(), "warn message")
+		logger.Error(context.Background(), "error message")
+
+		b, err := os.ReadFile(path)
+		if assert.Nil(t, err) {
+			assert.Regexp(t, `^{"time":"\d{2}:\d{2}:\d{2}","level":"ERROR","message":"error message","image_tag":"v1.0.0"}`+"\n$", string(b))
+		}
+	})
+	t.Run("invalid-level", func(t *testing.T) {
+		t.Setenv("APP_LOG_LEVEL", "verbose")
+
+		_, err := applog.NewFromEnv("APP_")
+		if assert.NotNil(t, err) {
+			assert.Equal(t, `invalid APP_LOG_LEVEL "verbose": invalid string for the log level`, err.Error())
+		}
+	})
+	t.Run("invalid-format", func(t *testing.T) {
+		t.Setenv("APP_LOG_FORMAT", "text")
+
+		_, err := applog.NewFromEnv("APP_")
+		if assert.NotNil(t, err) {
+			assert.Equal(t, `failed to create logger from environment variables: invalid log format: "text"`, err.Error())
+		}
+	})
+}
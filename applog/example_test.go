@@ -4,12 +4,17 @@ import (
 	"context"
 	"log"
 	"os"
+	"time"
 
 	"github.com/takuoki/golib/appctx"
 	"github.com/takuoki/golib/applog"
 )
 
 func Example() {
+	// Fix the current time to make the output stable.
+	defer applog.SetNow(func() time.Time {
+		return time.Date(2025, time.April, 1, 9, 30, 0, 0, time.UTC)
+	})()
 
 	// Get from environment variables, etc.
 	logLevel := "WARN"
@@ -20,22 +25,29 @@ func Example() {
 	if err != nil {
 		log.Fatalf("Fail to parse log level (logLevel=%q): %v", logLevel, err)
 	}
-	logger := applog.NewBasicLogger(
+	logger, err := applog.NewBasicLogger(
 		os.Stdout,
 		applog.LevelOption(lv),
-		applog.TimeFormatOption("YYYY-MM-DD HH:mm:ss"), // This is invalid format for example test.
+		applog.TimeFormatOption("2006-01-02 15:04:05"),
 		applog.ImageTagOption(imageTag),
 	)
+	if err != nil {
+		log.Fatalf("Fail to create logger: %v", err)
+	}
 
 	// Print log
 	ctx := context.Background()
 	logger.Error(ctx, "error message")
 
 	// Output:
-	// {"time":"YYYY-MM-DD HH:mm:ss","level":"ERROR","message":"error message","image_tag":"v1.0.0"}
+	// {"time":"2025-04-01 09:30:00","level":"ERROR","message":"error message","image_tag":"v1.0.0"}
 }
 
 func ExampleNewGoogleCloudLogger() {
+	// Fix the current time to make the output stable.
+	defer applog.SetNow(func() time.Time {
+		return time.Date(2025, time.April, 1, 9, 30, 0, 0, time.UTC)
+	})()
 
 	// Get from environment variables, etc.
 	logLevel := "WARN"
@@ -46,12 +58,15 @@ func ExampleNewGoogleCloudLogger() {
 	if err != nil {
 		log.Fatalf("Fail to parse log level (logLevel=%q): %v", logLevel, err)
 	}
-	logger := applog.NewGoogleCloudLogger(
+	logger, err := applog.NewGoogleCloudLogger(
 		os.Stdout,
 		applog.LevelOption(lv),
-		applog.TimeFormatOption("YYYY-MM-DDTHH:mm:ssZ"), // This is invalid format for example test.
+		applog.TimeFormatOption("2006-01-02T15:04:05Z07:00"),
 		applog.ImageTagOption(imageTag),
 	)
+	if err != nil {
+		log.Fatalf("Fail to create logger: %v", err)
+	}
 
 	// Print log with context including request ID
 	ctx := context.Background()
@@ -65,5 +80,5 @@ func ExampleNewGoogleCloudLogger() {
 	logger.Print(ctx, applog.ErrorLevel, "error message", customLabels)
 
 	// Output:
-	// {"timestamp":"YYYY-MM-DDTHH:mm:ssZ","severity":"ERROR","message":"error message","labels":{"image_tag":"v1.0.0","request_id":"req-12345","service":"api-server","version":"1.2.3"}}
+	// {"timestamp":"2025-04-01T09:30:00Z","severity":"ERROR","message":"error message","labels":{"image_tag":"v1.0.0","request_id":"req-12345","service":"api-server","version":"1.2.3"}}
 }
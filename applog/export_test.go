@@ -0,0 +1,10 @@
+package applog
+
+import "time"
+
+// SetNow replaces the current time function and returns a function to restore it.
+func SetNow(f func() time.Time) (restore func()) {
+	org := now
+	now = f
+	return func() { now = org }
+}
@@ -23,29 +23,32 @@ type googleCloudLogger struct {
 // It handles the necessity of output according to the log level,
 // and outputs context information to the log in common.
 // The output format follows Google Cloud Logging structure with severity and labels.
-func NewGoogleCloudLogger(w io.Writer, opts ...Option) Logger {
+// If an invalid option is specified, an error will be returned.
+func NewGoogleCloudLogger(w io.Writer, opts ...Option) (Logger, error) {
 	logger := &googleCloudLogger{
 		out:        w,
 		timeFormat: time.RFC3339Nano, // Google Cloud Logging prefers RFC3339Nano
 	}
-	for _, opt := range opts {
-		// googleCloudLogger option never returns an error
-		_ = opt(logger)
+	if err := ApplyOptions(logger, opts...); err != nil {
+		return nil, err
 	}
-	return logger
+	return logger, nil
 }
 
-func (l *googleCloudLogger) setLevel(lv Level) error {
+// SetLevel is a method to satisfy the Configurable interface.
+func (l *googleCloudLogger) SetLevel(lv Level) error {
 	l.level = lv
 	return nil
 }
 
-func (l *googleCloudLogger) setTimeFormat(format string) error {
+// SetTimeFormat is a method to satisfy the Configurable interface.
+func (l *googleCloudLogger) SetTimeFormat(format string) error {
 	l.timeFormat = format
 	return nil
 }
 
-func (l *googleCloudLogger) setImageTag(tag string) error {
+// SetImageTag is a method to satisfy the Configurable interface.
+func (l *googleCloudLogger) SetImageTag(tag string) error {
 	l.imageTag = tag
 	return nil
 }
@@ -126,7 +129,7 @@ func (l *googleCloudLogger) Print(ctx context.Context, lv Level, msg string, lab
 	}
 
 	log := googleCloudLog{
-		Timestamp: time.Now().Format(l.timeFormat),
+		Timestamp: now().Format(l.timeFormat),
 		Severity:  levelToGoogleCloudSeverity(lv),
 		Message:   msg,
 		Labels:    logLabels,
@@ -13,7 +13,10 @@ import (
 
 func TestNewGoogleCloudLogger(t *testing.T) {
 	var buf bytes.Buffer
-	logger := NewGoogleCloudLogger(&buf)
+	logger, err := NewGoogleCloudLogger(&buf)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
 
 	if logger == nil {
 		t.Error("NewGoogleCloudLogger should return a logger instance")
@@ -22,7 +25,10 @@ func TestNewGoogleCloudLogger(t *testing.T) {
 
 func TestGoogleCloudLogger_Print(t *testing.T) {
 	var buf bytes.Buffer
-	logger := NewGoogleCloudLogger(&buf, LevelOption(DebugLevel))
+	logger, err := NewGoogleCloudLogger(&buf, LevelOption(DebugLevel))
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
 
 	ctx := context.Background()
 	ctx = appctx.WithRequestID(ctx, "test-request-id")
@@ -49,7 +55,10 @@ func TestGoogleCloudLogger_Print(t *testing.T) {
 
 func TestGoogleCloudLogger_WithImageTag(t *testing.T) {
 	var buf bytes.Buffer
-	logger := NewGoogleCloudLogger(&buf, ImageTagOption("v1.2.3"))
+	logger, err := NewGoogleCloudLogger(&buf, ImageTagOption("v1.2.3"))
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
 
 	ctx := context.Background()
 	logger.Info(ctx, "test message")
@@ -66,7 +75,10 @@ func TestGoogleCloudLogger_WithImageTag(t *testing.T) {
 
 func TestGoogleCloudLogger_WithCustomLabels(t *testing.T) {
 	var buf bytes.Buffer
-	logger := NewGoogleCloudLogger(&buf)
+	logger, err := NewGoogleCloudLogger(&buf)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
 
 	ctx := context.Background()
 	customLabels := map[string]string{
@@ -92,7 +104,10 @@ func TestGoogleCloudLogger_WithCustomLabels(t *testing.T) {
 
 func TestGoogleCloudLogger_LevelFiltering(t *testing.T) {
 	var buf bytes.Buffer
-	logger := NewGoogleCloudLogger(&buf, LevelOption(WarnLevel))
+	logger, err := NewGoogleCloudLogger(&buf, LevelOption(WarnLevel))
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
 
 	ctx := context.Background()
 
@@ -134,7 +149,10 @@ func TestGoogleCloudLogger_LevelFiltering(t *testing.T) {
 
 func TestGoogleCloudLogger_Formatted(t *testing.T) {
 	var buf bytes.Buffer
-	logger := NewGoogleCloudLogger(&buf)
+	logger, err := NewGoogleCloudLogger(&buf)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
 
 	ctx := context.Background()
 	logger.Infof(ctx, "formatted message: %s, number: %d", "test", 42)
@@ -175,7 +193,10 @@ func TestLevelToGoogleCloudSeverity(t *testing.T) {
 func TestGoogleCloudLogger_TimeFormat(t *testing.T) {
 	var buf bytes.Buffer
 	customTimeFormat := "2006-01-02T15:04:05Z"
-	logger := NewGoogleCloudLogger(&buf, TimeFormatOption(customTimeFormat))
+	logger, err := NewGoogleCloudLogger(&buf, TimeFormatOption(customTimeFormat))
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
 
 	ctx := context.Background()
 	logger.Info(ctx, "test message")
@@ -190,3 +211,13 @@ func TestGoogleCloudLogger_TimeFormat(t *testing.T) {
 		t.Errorf("Timestamp should be in custom format %s, but got parsing error: %v", customTimeFormat, err)
 	}
 }
+
+func TestGoogleCloudLogger_OptionError(t *testing.T) {
+	var buf bytes.Buffer
+	if _, err := NewGoogleCloudLogger(&buf, LevelOption(UnknownLevel)); err == nil {
+		t.Error("NewGoogleCloudLogger should return an error for UnknownLevel")
+	}
+	if _, err := NewGoogleCloudLogger(&buf, TimeFormatOption("")); err == nil {
+		t.Error("NewGoogleCloudLogger should return an error for an empty time format")
+	}
+}
@@ -5,7 +5,10 @@
 // but refer to this library and create it for each application.
 package applog
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // Logger represents a logging interface that outputs log with log level.
 type Logger interface {
@@ -24,9 +27,7 @@ type Logger interface {
 	Tracef(ctx context.Context, format string, a ...interface{})
 
 	Print(ctx context.Context, lv Level, msg string, labels map[string]string)
-
-	// Option setter
-	setLevel(lv Level) error
-	setTimeFormat(format string) error
-	setImageTag(tag string) error
 }
+
+// now returns the current time. It is replaced in tests to make the output stable.
+var now = time.Now
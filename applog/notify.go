@@ -0,0 +1,220 @@
+package applog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/takuoki/golib/appctx"
+	"github.com/takuoki/golib/notice"
+)
+
+type notifyLogger struct {
+	Logger
+	notifier    notice.ContextNotifier
+	level       Level
+	dedupWindow time.Duration
+
+	mu        sync.Mutex
+	notified  map[string]time.Time
+	pruneSize int
+}
+
+// minPruneSize is the number of the notified entries below which expired entries are not pruned.
+const minPruneSize = 64
+
+// NotifyOption is an option for NewNotifyLogger.
+type NotifyOption func(*notifyLogger) error
+
+// NotifyLevelOption sets the minimum log level to notify.
+// The default is ErrorLevel.
+func NotifyLevelOption(lv Level) NotifyOption {
+	return func(l *notifyLogger) error {
+		if lv < TraceLevel || lv > CriticalLevel {
+			return fmt.Errorf("invalid log level: %d", lv)
+		}
+		l.level = lv
+		return nil
+	}
+}
+
+// NotifyDedupWindowOption sets the window in which the same log entries are notified only once.
+// The log entries are regarded as the same if their levels and messages are equal.
+// The default is 1 minute. Specify 0 to disable deduplication.
+func NotifyDedupWindowOption(window time.Duration) NotifyOption {
+	return func(l *notifyLogger) error {
+		if window < 0 {
+			return fmt.Errorf("invalid dedup window: %s", window)
+		}
+		l.dedupWindow = window
+		return nil
+	}
+}
+
+// NewNotifyLogger creates a logger that outputs log with `logger`
+// and forwards log entries to `notifier`.
+// CriticalLevel entries are notified with Critical and the other entries with Error.
+// The error passed to the notifier is *EntryError, and the context of the log entry
+// is passed as well (see notice.NewContextNotifier).
+// If the notification fails, it is output to `logger` as a warning.
+//
+// The notifier is called synchronously in the log call, so wrap a slow notifier
+// (ex. Slack or webhook notifier, which retries on failure) with notice.NewAsyncNotifier
+// not to block the caller.
+func NewNotifyLogger(logger Logger, notifier notice.Notifier, opts ...NotifyOption) (Logger, error) {
+	if logger == nil {
+		return nil, errors.New("logger is nil")
+	}
+	if notifier == nil {
+		return nil, errors.New("notifier is nil")
+	}
+	l := &notifyLogger{
+		Logger:      logger,
+		notifier:    notice.NewContextNotifier(notifier),
+		level:       ErrorLevel,
+		dedupWindow: time.Minute,
+		notified:    map[string]time.Time{},
+		pruneSize:   minPruneSize,
+	}
+	for _, opt := range opts {
+		if err := opt(l); err != nil {
+			return nil, err
+		}
+	}
+	return l, nil
+}
+
+// EntryError is an error that represents a log entry notified by NewNotifyLogger.
+type EntryError struct {
+	Level     Level
+	Message   string
+	Labels    map[string]string
+	RequestID string
+}
+
+// Error is a method to satisfy the error interface.
+// It returns the message followed by the request ID and labels.
+func (e *EntryError) Error() string {
+	labels := make(map[string]string, len(e.Labels)+1)
+	for k, v := range e.Labels {
+		labels[k] = v
+	}
+	if e.RequestID != "" {
+		labels["request_id"] = e.RequestID
+	}
+	return e.Message + formatLabels(labels)
+}
+
+func (l *notifyLogger) Critical(ctx context.Context, msg string) {
+	l.Print(ctx, CriticalLevel, msg, nil)
+}
+
+func (l *notifyLogger) Error(ctx context.Context, msg string) {
+	l.Print(ctx, ErrorLevel, msg, nil)
+}
+
+func (l *notifyLogger) Warn(ctx context.Context, msg string) {
+	l.Print(ctx, WarnLevel, msg, nil)
+}
+
+func (l *notifyLogger) Info(ctx context.Context, msg string) {
+	l.Print(ctx, InfoLevel, msg, nil)
+}
+
+func (l *notifyLogger) Debug(ctx context.Context, msg string) {
+	l.Print(ctx, DebugLevel, msg, nil)
+}
+
+func (l *notifyLogger) Trace(ctx context.Context, msg string) {
+	l.Print(ctx, TraceLevel, msg, nil)
+}
+
+func (l *notifyLogger) Criticalf(ctx context.Context, format string, a ...interface{}) {
+	l.printf(ctx, CriticalLevel, format, a...)
+}
+
+func (l *notifyLogger) Errorf(ctx context.Context, format string, a ...interface{}) {
+	l.printf(ctx, ErrorLevel, format, a...)
+}
+
+func (l *notifyLogger) Warnf(ctx context.Context, format string, a ...interface{}) {
+	l.printf(ctx, WarnLevel, format, a...)
+}
+
+func (l *notifyLogger) Infof(ctx context.Context, format string, a ...interface{}) {
+	l.printf(ctx, InfoLevel, format, a...)
+}
+
+func (l *notifyLogger) Debugf(ctx context.Context, format string, a ...interface{}) {
+	l.printf(ctx, DebugLevel, format, a...)
+}
+
+func (l *notifyLogger) Tracef(ctx context.Context, format string, a ...interface{}) {
+	l.printf(ctx, TraceLevel, format, a...)
+}
+
+func (l *notifyLogger) printf(ctx context.Context, lv Level, format string, a ...interface{}) {
+	l.Print(ctx, lv, fmt.Sprintf(format, a...), nil)
+}
+
+func (l *notifyLogger) Print(ctx context.Context, lv Level, msg string, labels map[string]string) {
+	l.Logger.Print(ctx, lv, msg, labels)
+
+	if !shouldPrint(l.level, lv) || l.isDuplicate(lv, msg) {
+		return
+	}
+
+	err := &EntryError{
+		Level:     lv,
+		Message:   msg,
+		Labels:    labels,
+		RequestID: appctx.RequestID(ctx),
+	}
+	var nerr error
+	if lv >= CriticalLevel {
+		nerr = l.notifier.CriticalContext(ctx, err)
+	} else {
+		nerr = l.notifier.ErrorContext(ctx, err)
+	}
+	if nerr != nil {
+		// Use the wrapped logger so that the failure is not notified again.
+		l.Logger.Warnf(ctx, "failed to notify log entry: %v", nerr)
+	}
+}
+
+// isDuplicate reports whether the same entry has been notified within the dedup window,
+// and records the entry as notified otherwise.
+func (l *notifyLogger) isDuplicate(lv Level, msg string) bool {
+	if l.dedupWindow <= 0 {
+		return false
+	}
+
+	key := lv.String() + ":" + msg
+	t := now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if last, ok := l.notified[key]; ok && t.Sub(last) < l.dedupWindow {
+		return true
+	}
+	l.notified[key] = t
+	l.prune(t)
+	return false
+}
+
+// prune deletes the expired entries only when the number of the entries exceeds the prune size,
+// so that the whole entries are not scanned on every notification.
+func (l *notifyLogger) prune(t time.Time) {
+	if len(l.notified) <= l.pruneSize {
+		return
+	}
+	for k, last := range l.notified {
+		if t.Sub(last) >= l.dedupWindow {
+			delete(l.notified, k)
+		}
+	}
+	l.pruneSize = max(minPruneSize, 2*len(l.notified))
+}
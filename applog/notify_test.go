@@ -0,0 +1,159 @@
+package applog_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/takuoki/golib/appctx"
+	"github.com/takuoki/golib/applog"
+	"github.com/takuoki/golib/notice"
+)
+
+type notification struct {
+	critical bool
+	err      error
+}
+
+type fakeNotifier struct {
+	notifications []notification
+	err           error
+}
+
+func (n *fakeNotifier) Error(err error) error {
+	n.notifications = append(n.notifications, notification{err: err})
+	return n.err
+}
+
+func (n *fakeNotifier) Critical(err error) error {
+	n.notifications = append(n.notifications, notification{critical: true, err: err})
+	return n.err
+}
+
+func newNotifyLogger(t *testing.T, n *fakeNotifier, opts ...applog.NotifyOption) (applog.Logger, *bytes.Buffer) {
+	buf := &bytes.Buffer{}
+	logger, err := applog.NewSimpleLogger(buf, applog.LevelOption(applog.TraceLevel))
+	if err != nil {
+		t.Fatalf("error occurred in NewSimpleLogger: %v", err)
+	}
+	l, err := applog.NewNotifyLogger(logger, n, opts...)
+	if err != nil {
+		t.Fatalf("error occurred in NewNotifyLogger: %v", err)
+	}
+	return l, buf
+}
+
+func TestNotifyLogger(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		n := &fakeNotifier{}
+		l, buf := newNotifyLogger(t, n)
+
+		ctx := appctx.WithRequestID(context.Background(), "req-1")
+		l.Warn(ctx, "warn message")
+		l.Errorf(ctx, "error message: %d", 1)
+		l.Print(ctx, applog.CriticalLevel, "critical message", map[string]string{"foo": "abc"})
+
+		assert.Equal(t, "warn message\nerror message: 1\ncritical message (foo: abc)\n", buf.String())
+		if assert.Len(t, n.notifications, 2) {
+			assert.False(t, n.notifications[0].critical)
+			assert.Equal(t, "error message: 1 (request_id: req-1)", n.notifications[0].err.Error())
+			assert.Equal(t, "req-1", appctx.RequestID(notice.ContextFromError(n.notifications[0].err)))
+			assert.True(t, n.notifications[1].critical)
+			assert.Equal(t, "critical message (foo: abc, request_id: req-1)", n.notifications[1].err.Error())
+
+			var e *applog.EntryError
+			if assert.True(t, errors.As(n.notifications[1].err, &e)) {
+				assert.Equal(t, applog.CriticalLevel, e.Level)
+				assert.Equal(t, "critical message", e.Message)
+				assert.Equal(t, map[string]string{"foo": "abc"}, e.Labels)
+				assert.Equal(t, "req-1", e.RequestID)
+			}
+		}
+	})
+	t.Run("level", func(t *testing.T) {
+		n := &fakeNotifier{}
+		l, _ := newNotifyLogger(t, n, applog.NotifyLevelOption(applog.CriticalLevel))
+
+		l.Error(context.Background(), "error message")
+		l.Critical(context.Background(), "critical message")
+
+		if assert.Len(t, n.notifications, 1) {
+			assert.True(t, n.notifications[0].critical)
+		}
+	})
+	t.Run("dedup", func(t *testing.T) {
+		current := time.Date(2025, time.April, 1, 9, 30, 0, 0, time.UTC)
+		defer applog.SetNow(func() time.Time { return current })()
+
+		n := &fakeNotifier{}
+		l, _ := newNotifyLogger(t, n, applog.NotifyDedupWindowOption(time.Minute))
+
+		l.Error(context.Background(), "error message")
+		l.Error(context.Background(), "error message")
+		l.Critical(context.Background(), "error message")
+		l.Error(context.Background(), "another message")
+		current = current.Add(time.Minute)
+		l.Error(context.Background(), "error message")
+
+		assert.Len(t, n.notifications, 4)
+	})
+	t.Run("dedup-many-entries", func(t *testing.T) {
+		current := time.Date(2025, time.April, 1, 9, 30, 0, 0, time.UTC)
+		defer applog.SetNow(func() time.Time { return current })()
+
+		n := &fakeNotifier{}
+		l, _ := newNotifyLogger(t, n, applog.NotifyDedupWindowOption(time.Minute))
+
+		for i := 0; i < 100; i++ {
+			l.Errorf(context.Background(), "error message %d", i)
+		}
+		current = current.Add(time.Minute)
+		for i := 0; i < 100; i++ {
+			l.Errorf(context.Background(), "error message %d", i)
+			l.Errorf(context.Background(), "error message %d", i)
+		}
+
+		assert.Len(t, n.notifications, 200)
+	})
+	t.Run("no-dedup", func(t *testing.T) {
+		n := &fakeNotifier{}
+		l, _ := newNotifyLogger(t, n, applog.NotifyDedupWindowOption(0))
+
+		l.Error(context.Background(), "error message")
+		l.Error(context.Background(), "error message")
+
+		assert.Len(t, n.notifications, 2)
+	})
+	t.Run("notification-error", func(t *testing.T) {
+		n := &fakeNotifier{err: errors.New("unavailable")}
+		l, buf := newNotifyLogger(t, n)
+
+		l.Error(context.Background(), "error message")
+
+		assert.Equal(t, "error message\nfailed to notify log entry: unavailable\n", buf.String())
+		assert.Len(t, n.notifications, 1)
+	})
+}
+
+func TestNewNotifyLoggerError(t *testing.T) {
+	logger, err := applog.NewSimpleLogger(&bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("error occurred in NewSimpleLogger: %v", err)
+	}
+
+	t.Run("nil-notifier", func(t *testing.T) {
+		_, err := applog.NewNotifyLogger(logger, nil)
+		if assert.NotNil(t, err) {
+			assert.Equal(t, "notifier is nil", err.Error())
+		}
+	})
+	t.Run("level", func(t *testing.T) {
+		_, err := applog.NewNotifyLogger(logger, &fakeNotifier{}, applog.NotifyLevelOption(applog.UnknownLevel))
+		if assert.NotNil(t, err) {
+			assert.Equal(t, "invalid log level: -3", err.Error())
+		}
+	})
+}
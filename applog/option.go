@@ -1,25 +1,74 @@
 package applog
 
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Configurable represents a logger that can be configured by Option.
+// Implement this interface to make a custom logger accept Option,
+// and apply the options with ApplyOptions in its constructor.
+// Each setter should return an error if the setting is not available for the logger.
+type Configurable interface {
+	SetLevel(lv Level) error
+	SetTimeFormat(format string) error
+	SetImageTag(tag string) error
+}
+
 // Option is an option for logger generation.
-type Option func(Logger) error
+// The value of the option is validated before it is set to the logger.
+type Option func(Configurable) error
+
+// ApplyOptions applies the options to the logger in order.
+// It stops and returns the error when any option fails.
+func ApplyOptions(c Configurable, opts ...Option) error {
+	for _, opt := range opts {
+		if err := opt(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
 // LevelOption sets the log level that the logger outputs.
+// It returns an error if the level is not one of the defined levels except UnknownLevel.
 func LevelOption(lv Level) Option {
-	return func(l Logger) error {
-		return l.setLevel(lv)
+	return func(c Configurable) error {
+		if lv < TraceLevel || lv > CriticalLevel {
+			return fmt.Errorf("invalid log level: %d", lv)
+		}
+		return c.SetLevel(lv)
 	}
 }
 
 // TimeFormatOption sets the time format that the logger outputs.
+// It returns an error if the format is empty or contains no element of the Go time layout
+// (ex. "YYYY-MM-DD"). See https://pkg.go.dev/time#pkg-constants for the layout.
 func TimeFormatOption(format string) Option {
-	return func(l Logger) error {
-		return l.setTimeFormat(format)
+	return func(c Configurable) error {
+		if err := validateTimeFormat(format); err != nil {
+			return err
+		}
+		return c.SetTimeFormat(format)
 	}
 }
 
 // ImageTagOption sets the image tag that the logger outputs.
 func ImageTagOption(tag string) Option {
-	return func(l Logger) error {
-		return l.setImageTag(tag)
+	return func(c Configurable) error {
+		return c.SetImageTag(tag)
+	}
+}
+
+func validateTimeFormat(format string) error {
+	if format == "" {
+		return errors.New("time format is empty")
+	}
+	// Every layout element is formatted differently from its reference value with this time.
+	t := time.Date(2001, time.February, 3, 4, 5, 6, 7, time.FixedZone("XST", 3600))
+	if t.Format(format) == format {
+		return fmt.Errorf("invalid time format %q: no layout element found", format)
 	}
+	return nil
 }
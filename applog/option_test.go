@@ -0,0 +1,53 @@
+package applog_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/takuoki/golib/applog"
+)
+
+type customLogger struct {
+	level      applog.Level
+	timeFormat string
+}
+
+func (l *customLogger) SetLevel(lv applog.Level) error {
+	l.level = lv
+	return nil
+}
+
+func (l *customLogger) SetTimeFormat(format string) error {
+	l.timeFormat = format
+	return nil
+}
+
+func (l *customLogger) SetImageTag(tag string) error {
+	return errors.New("ImageTagOption is not available for customLogger")
+}
+
+func TestApplyOptions(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		l := &customLogger{}
+		err := applog.ApplyOptions(l, applog.LevelOption(applog.WarnLevel), applog.TimeFormatOption("15:04"))
+		assert.Nil(t, err)
+		assert.Equal(t, applog.WarnLevel, l.level)
+		assert.Equal(t, "15:04", l.timeFormat)
+	})
+	t.Run("invalid-value", func(t *testing.T) {
+		l := &customLogger{}
+		err := applog.ApplyOptions(l, applog.LevelOption(applog.CriticalLevel+1))
+		if assert.NotNil(t, err) {
+			assert.Equal(t, "invalid log level: 4", err.Error())
+		}
+		assert.Equal(t, applog.InfoLevel, l.level, "invalid value must not be set")
+	})
+	t.Run("unavailable", func(t *testing.T) {
+		l := &customLogger{}
+		err := applog.ApplyOptions(l, applog.ImageTagOption("v1.0.0"))
+		if assert.NotNil(t, err) {
+			assert.Equal(t, "ImageTagOption is not available for customLogger", err.Error())
+		}
+	})
+}
@@ -18,28 +18,30 @@ type simpleLogger struct {
 
 // NewSimpleLogger creates a simple logger that outputs only message.
 // It handles the necessity of output according to the log level.
+// If an invalid or unavailable option is specified, an error will be returned.
 func NewSimpleLogger(w io.Writer, opts ...Option) (Logger, error) {
 	logger := &simpleLogger{
 		out: w,
 	}
-	for _, opt := range opts {
-		if err := opt(logger); err != nil {
-			return nil, err
-		}
+	if err := ApplyOptions(logger, opts...); err != nil {
+		return nil, err
 	}
 	return logger, nil
 }
 
-func (l *simpleLogger) setLevel(lv Level) error {
+// SetLevel is a method to satisfy the Configurable interface.
+func (l *simpleLogger) SetLevel(lv Level) error {
 	l.level = lv
 	return nil
 }
 
-func (l *simpleLogger) setTimeFormat(format string) error {
+// SetTimeFormat is a method to satisfy the Configurable interface.
+func (l *simpleLogger) SetTimeFormat(format string) error {
 	return errors.New("TimeFormatOption is not available for simpleLogger")
 }
 
-func (l *simpleLogger) setImageTag(tag string) error {
+// SetImageTag is a method to satisfy the Configurable interface.
+func (l *simpleLogger) SetImageTag(tag string) error {
 	return errors.New("ImageTagOption is not available for simpleLogger")
 }
 
@@ -100,24 +102,29 @@ func (l *simpleLogger) Print(ctx context.Context, lv Level, msg string, labels m
 		return
 	}
 
-	labelMsg := ""
-	if len(labels) > 0 {
-		keys := make([]string, len(labels))
-		i := 0
-		for key := range labels {
-			keys[i] = key
-			i++
-		}
-		sort.Strings(keys)
-
-		ls := make([]string, len(labels))
-		for i, key := range keys {
-			ls[i] = fmt.Sprintf("%s: %s", key, labels[key])
-		}
-		labelMsg = fmt.Sprintf(" (%s)", strings.Join(ls, ", "))
-	}
-
 	l.mu.Lock()
 	defer l.mu.Unlock()
-	fmt.Fprintln(l.out, msg+labelMsg) //nolint:errcheck
+	fmt.Fprintln(l.out, msg+formatLabels(labels)) //nolint:errcheck
+}
+
+// formatLabels returns labels sorted by key in the format " (key1: value1, key2: value2)".
+// If labels is empty, it returns an empty string.
+func formatLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+
+	keys := make([]string, len(labels))
+	i := 0
+	for key := range labels {
+		keys[i] = key
+		i++
+	}
+	sort.Strings(keys)
+
+	ls := make([]string, len(labels))
+	for i, key := range keys {
+		ls[i] = fmt.Sprintf("%s: %s", key, labels[key])
+	}
+	return fmt.Sprintf(" (%s)", strings.Join(ls, ", "))
 }
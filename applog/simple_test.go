@@ -65,7 +65,7 @@ func TestSimpleLoggerPrint(t *testing.T) {
 
 func TestSimpleLoggerLevel(t *testing.T) {
 	newLogger := func(w io.Writer) applog.Logger {
-		l, err := applog.NewSimpleLogger(w, applog.LevelOption(applog.UnknownLevel))
+		l, err := applog.NewSimpleLogger(w, applog.LevelOption(applog.TraceLevel))
 		if err != nil {
 			t.Fatalf("error occurred in NewSimpleLogger: %v", err)
 		}
@@ -134,9 +134,16 @@ func TestSimpleLoggerLevel(t *testing.T) {
 }
 
 func TestSimpleLoggerOptionError(t *testing.T) {
+	t.Run("level", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		_, err := applog.NewSimpleLogger(buf, applog.LevelOption(applog.UnknownLevel))
+		if assert.NotNil(t, err) {
+			assert.Equal(t, "invalid log level: -3", err.Error())
+		}
+	})
 	t.Run("timeFormat", func(t *testing.T) {
 		buf := &bytes.Buffer{}
-		_, err := applog.NewSimpleLogger(buf, applog.TimeFormatOption("dummy"))
+		_, err := applog.NewSimpleLogger(buf, applog.TimeFormatOption("15:04:05"))
 		if assert.NotNil(t, err) {
 			assert.Equal(t, "TimeFormatOption is not available for simpleLogger", err.Error())
 		}
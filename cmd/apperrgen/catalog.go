@@ -0,0 +1,147 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"gopkg.in/yaml.v3"
+
+	"github.com/takuoki/golib/apperr"
+)
+
+// catalog is the error catalog file.
+type catalog struct {
+	Errors []definition `json:"errors" yaml:"errors"`
+}
+
+// definition is a definition of the error in the catalog file.
+type definition struct {
+	// Name is the Go identifier of the error (ex. "NotFound").
+	Name       string `json:"name" yaml:"name"`
+	DetailCode string `json:"detail_code" yaml:"detail_code"`
+	// GRPCCode is the name of the gRPC code (ex. "NotFound").
+	GRPCCode string `json:"grpc_code" yaml:"grpc_code"`
+	// Type is "client" or "server".
+	Type        string `json:"type" yaml:"type"`
+	Message     string `json:"message" yaml:"message"`
+	Description string `json:"description" yaml:"description"`
+
+	code codes.Code
+	typ  apperr.Type
+}
+
+// loadCatalog reads the catalog file in YAML or JSON according to the extension.
+func loadCatalog(path string) (*catalog, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read catalog: %w", err)
+	}
+
+	var c catalog
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = json.Unmarshal(b, &c)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(b, &c)
+	default:
+		return nil, fmt.Errorf("unsupported catalog extension: %q", filepath.Ext(path))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse catalog: %w", err)
+	}
+
+	if err := c.validate(); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// validate validates the definitions and sets the parsed code and type.
+func (c *catalog) validate() error {
+	if len(c.Errors) == 0 {
+		return errors.New("no errors are defined in catalog")
+	}
+	registry := apperr.NewRegistry()
+	idents := map[string]string{} // generated identifier -> name of the error
+	for i := range c.Errors {
+		d := &c.Errors[i]
+		if !token.IsIdentifier(d.Name) || !token.IsExported(d.Name) {
+			return fmt.Errorf("invalid name of errors[%d]: %q must be an exported Go identifier", i, d.Name)
+		}
+
+		code, ok := parseCode(d.GRPCCode)
+		if !ok {
+			return fmt.Errorf("invalid grpc_code of %s: %q", d.Name, d.GRPCCode)
+		}
+		d.code = code
+
+		switch strings.ToLower(d.Type) {
+		case "client":
+			d.typ = apperr.ClientError
+		case "server":
+			d.typ = apperr.ServerError
+		default:
+			return fmt.Errorf("invalid type of %s: %q", d.Name, d.Type)
+		}
+
+		for _, ident := range d.identifiers() {
+			if name, ok := idents[ident]; ok {
+				if name == d.Name {
+					return fmt.Errorf("duplicate name: %s", d.Name)
+				}
+				return fmt.Errorf("duplicate identifier %s: generated for both %s and %s", ident, name, d.Name)
+			}
+			idents[ident] = d.Name
+		}
+
+		if _, err := registry.Register(d.apperrDefinition()); err != nil {
+			return fmt.Errorf("invalid definition of %s: %w", d.Name, err)
+		}
+	}
+	return nil
+}
+
+// identifiers returns the Go identifiers generated for the error.
+// It must be consistent with goTemplate.
+func (d *definition) identifiers() []string {
+	idents := []string{"DetailCode" + d.Name}
+	if d.typ == apperr.ServerError {
+		return append(idents, "New"+d.Name, "Wrap"+d.Name)
+	}
+	return append(idents, d.Name)
+}
+
+func (d *definition) apperrDefinition() apperr.Definition {
+	return apperr.Definition{
+		DetailCode:  d.DetailCode,
+		Code:        d.code,
+		Type:        d.typ,
+		Message:     d.Message,
+		Description: d.Description,
+	}
+}
+
+// registry returns the registry of the definitions.
+func (c *catalog) registry() *apperr.Registry {
+	r := apperr.NewRegistry()
+	for i := range c.Errors {
+		r.MustRegister(c.Errors[i].apperrDefinition())
+	}
+	return r
+}
+
+// parseCode parses the name of the gRPC code returned by codes.Code.String.
+func parseCode(s string) (codes.Code, bool) {
+	for c := codes.OK; c <= codes.Unauthenticated; c++ {
+		if c != codes.OK && c.String() == s {
+			return c, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/takuoki/golib/apperr"
+)
+
+const header = "Code generated by apperrgen. DO NOT EDIT."
+
+var funcs = template.FuncMap{
+	"quote":   strconv.Quote,
+	"comment": comment,
+	"isServer": func(d definition) bool {
+		return d.typ == apperr.ServerError
+	},
+}
+
+var goTemplate = template.Must(template.New("go").Funcs(funcs).Parse(`// {{.Header}}
+
+package {{.Package}}
+
+import (
+	"github.com/takuoki/golib/apperr"
+	"google.golang.org/grpc/codes"
+)
+
+// Detail codes.
+const (
+{{- range .Errors}}
+	DetailCode{{.Name}} = {{quote .DetailCode}}
+{{- end}}
+)
+{{range .Errors}}
+{{- if isServer .}}
+// New{{.Name}} creates the server error {{.DetailCode}} with the log.
+{{- if .Description}}
+{{comment .Description}}
+{{- end}}
+func New{{.Name}}(log string, opts ...apperr.Option) apperr.Err {
+	return apperr.NewServerError(codes.{{.GRPCCode}}, DetailCode{{.Name}}, {{quote .Message}}, log, opts...)
+}
+
+// Wrap{{.Name}} creates the server error {{.DetailCode}} caused by the error.
+func Wrap{{.Name}}(cause error, opts ...apperr.Option) apperr.Err {
+	return apperr.WrapServerError(cause, codes.{{.GRPCCode}}, DetailCode{{.Name}}, {{quote .Message}}, "", opts...)
+}
+{{else}}
+// {{.Name}} is the client error {{.DetailCode}}.
+{{- if .Description}}
+{{comment .Description}}
+{{- end}}
+var {{.Name}} = apperr.NewClientError(codes.{{.GRPCCode}}, DetailCode{{.Name}}, {{quote .Message}})
+{{end}}
+{{- end}}`))
+
+// generateGo writes the Go source that declares the detail codes, the client errors as variables
+// and the constructors of the server errors.
+func generateGo(w io.Writer, c *catalog, pkg string) error {
+	var buf bytes.Buffer
+	if err := goTemplate.Execute(&buf, struct {
+		Header  string
+		Package string
+		Errors  []definition
+	}{
+		Header:  header,
+		Package: pkg,
+		Errors:  c.Errors,
+	}); err != nil {
+		return fmt.Errorf("failed to generate go source: %w", err)
+	}
+
+	b, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("failed to format go source: %w", err)
+	}
+	if _, err := w.Write(b); err != nil {
+		return fmt.Errorf("failed to write go source: %w", err)
+	}
+	return nil
+}
+
+var tsTemplate = template.Must(template.New("ts").Funcs(funcs).Parse(`// {{.Header}}
+
+export const ErrorCode = {
+{{- range .Errors}}
+  {{.Name}}: {{quote .DetailCode}},
+{{- end}}
+} as const;
+
+export type ErrorCode = (typeof ErrorCode)[keyof typeof ErrorCode];
+
+export const errorMessages: Record<ErrorCode, string> = {
+{{- range .Errors}}
+  {{quote .DetailCode}}: {{quote .Message}},
+{{- end}}
+};
+`))
+
+// generateTypeScript writes the TypeScript source that declares the detail codes and the messages.
+func generateTypeScript(w io.Writer, c *catalog) error {
+	if err := tsTemplate.Execute(w, struct {
+		Header string
+		Errors []definition
+	}{
+		Header: header,
+		Errors: c.Errors,
+	}); err != nil {
+		return fmt.Errorf("failed to generate typescript source: %w", err)
+	}
+	return nil
+}
+
+// generateJSON writes the definitions in the format of apperr.Registry.WriteJSON.
+func generateJSON(w io.Writer, c *catalog) error {
+	return c.registry().WriteJSON(w)
+}
+
+// comment returns the text as Go line comments.
+func comment(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimRight("// "+l, " ")
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,93 @@
+// Command apperrgen generates Go source of apperr errors from an error catalog file,
+// so that the errors are defined once and shared between services and clients.
+//
+// Usage:
+//
+//	apperrgen -in errors.yaml -out errors_gen.go [-pkg errs] [-ts errors.ts] [-json errors.json]
+//
+// The catalog file is YAML or JSON according to the extension:
+//
+//	errors:
+//	  - name: NotFound
+//	    detail_code: E0001
+//	    grpc_code: NotFound
+//	    type: client
+//	    message: not found
+//	    description: The resource does not exist.
+//
+// Client errors are generated as variables created by apperr.NewClientError,
+// and server errors as constructors using apperr.NewServerError and apperr.WrapServerError.
+// The TypeScript or JSON artifact for frontends is generated optionally.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, "apperrgen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	fs := flag.NewFlagSet("apperrgen", flag.ContinueOnError)
+	in := fs.String("in", "", "error catalog file (.yaml, .yml or .json)")
+	out := fs.String("out", "", "output Go file")
+	pkg := fs.String("pkg", "", "package name of the Go file (default: directory name of the output)")
+	ts := fs.String("ts", "", "output TypeScript file (optional)")
+	jsonOut := fs.String("json", "", "output JSON file (optional)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *in == "" || *out == "" {
+		return errors.New("-in and -out are required")
+	}
+	if *pkg == "" {
+		abs, err := filepath.Abs(*out)
+		if err != nil {
+			return err
+		}
+		*pkg = filepath.Base(filepath.Dir(abs))
+	}
+
+	c, err := loadCatalog(*in)
+	if err != nil {
+		return err
+	}
+
+	if err := writeFile(*out, func(w io.Writer) error { return generateGo(w, c, *pkg) }); err != nil {
+		return err
+	}
+	if *ts != "" {
+		if err := writeFile(*ts, func(w io.Writer) error { return generateTypeScript(w, c) }); err != nil {
+			return err
+		}
+	}
+	if *jsonOut != "" {
+		if err := writeFile(*jsonOut, func(w io.Writer) error { return generateJSON(w, c) }); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeFile generates the content into a buffer and writes it to the file,
+// so that the file is not left truncated when the generation fails.
+func writeFile(path string, generate func(w io.Writer) error) error {
+	var buf bytes.Buffer
+	if err := generate(&buf); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "errs")
+	if !assert.NoError(t, os.Mkdir(dir, 0o755)) {
+		return
+	}
+	out := filepath.Join(dir, "errors_gen.go")
+	ts := filepath.Join(dir, "errors.ts")
+	jsonOut := filepath.Join(dir, "errors.json")
+
+	err := run([]string{"-in", "testdata/errors.yaml", "-out", out, "-ts", ts, "-json", jsonOut})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	for got, golden := range map[string]string{
+		out: "testdata/errors_gen.go.golden",
+		ts:  "testdata/errors.ts.golden",
+	} {
+		assertFile(t, golden, got)
+	}
+
+	b, err := os.ReadFile(jsonOut)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Contains(t, string(b), `"detail_code": "S0001"`)
+	assert.Contains(t, string(b), `"type": "server-error"`)
+}
+
+func TestRun_Error(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "errors_gen.go")
+	testcases := map[string]struct {
+		args []string
+	}{
+		"no-input":        {args: []string{"-out", out}},
+		"no-output":       {args: []string{"-in", "testdata/errors.yaml"}},
+		"file-not-found":  {args: []string{"-in", "testdata/not_found.yaml", "-out", out}},
+		"unsupported-ext": {args: []string{"-in", "testdata/errors.ts.golden", "-out", out}},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			assert.Error(t, run(tc.args))
+		})
+	}
+}
+
+func TestRun_KeepOutputOnError(t *testing.T) {
+	dir := t.TempDir()
+	in := filepath.Join(dir, "errors.json")
+	out := filepath.Join(dir, "errors_gen.go")
+	if !assert.NoError(t, os.WriteFile(in, []byte(`{"errors": [{"name": "NotFound", "detail_code": "E0001", "grpc_code": "NotFound", "type": "client"}]}`), 0o644)) {
+		return
+	}
+	if !assert.NoError(t, os.WriteFile(out, []byte("existing"), 0o644)) {
+		return
+	}
+
+	assert.Error(t, run([]string{"-in", in, "-out", out, "-pkg", "invalid package"}))
+
+	b, err := os.ReadFile(out)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "existing", string(b), "The output must not be changed when the generation fails.")
+	}
+}
+
+func TestLoadCatalog(t *testing.T) {
+	testcases := map[string]struct {
+		content string
+		want    string
+	}{
+		"json": {
+			content: `{"errors": [{"name": "NotFound", "detail_code": "E0001", "grpc_code": "NotFound", "type": "client", "message": "not found"}]}`,
+		},
+		"no-errors": {
+			content: `{"errors": []}`,
+			want:    "no errors are defined in catalog",
+		},
+		"invalid-name": {
+			content: `{"errors": [{"name": "notFound", "detail_code": "E0001", "grpc_code": "NotFound", "type": "client"}]}`,
+			want:    `invalid name of errors[0]: "notFound" must be an exported Go identifier`,
+		},
+		"duplicate-name": {
+			content: `{"errors": [
+				{"name": "NotFound", "detail_code": "E0001", "grpc_code": "NotFound", "type": "client"},
+				{"name": "NotFound", "detail_code": "E0002", "grpc_code": "NotFound", "type": "client"}
+			]}`,
+			want: "duplicate name: NotFound",
+		},
+		"duplicate-constructor": {
+			content: `{"errors": [
+				{"name": "NewInternal", "detail_code": "E0001", "grpc_code": "NotFound", "type": "client"},
+				{"name": "Internal", "detail_code": "S0001", "grpc_code": "Internal", "type": "server"}
+			]}`,
+			want: "duplicate identifier NewInternal: generated for both NewInternal and Internal",
+		},
+		"duplicate-detail-code-constant": {
+			content: `{"errors": [
+				{"name": "DetailCodeNotFound", "detail_code": "E0001", "grpc_code": "NotFound", "type": "client"},
+				{"name": "NotFound", "detail_code": "E0002", "grpc_code": "NotFound", "type": "client"}
+			]}`,
+			want: "duplicate identifier DetailCodeNotFound: generated for both DetailCodeNotFound and NotFound",
+		},
+		"invalid-grpc-code": {
+			content: `{"errors": [{"name": "NotFound", "detail_code": "E0001", "grpc_code": "NOT_FOUND", "type": "client"}]}`,
+			want:    `invalid grpc_code of NotFound: "NOT_FOUND"`,
+		},
+		"ok-grpc-code": {
+			content: `{"errors": [{"name": "NotFound", "detail_code": "E0001", "grpc_code": "OK", "type": "client"}]}`,
+			want:    `invalid grpc_code of NotFound: "OK"`,
+		},
+		"invalid-type": {
+			content: `{"errors": [{"name": "NotFound", "detail_code": "E0001", "grpc_code": "NotFound", "type": "unknown"}]}`,
+			want:    `invalid type of NotFound: "unknown"`,
+		},
+		"empty-detail-code": {
+			content: `{"errors": [{"name": "NotFound", "grpc_code": "NotFound", "type": "client"}]}`,
+			want:    "invalid definition of NotFound: detail code is empty",
+		},
+		"duplicate-detail-code": {
+			content: `{"errors": [
+				{"name": "NotFound", "detail_code": "E0001", "grpc_code": "NotFound", "type": "client"},
+				{"name": "Internal", "detail_code": "E0001", "grpc_code": "Internal", "type": "server"}
+			]}`,
+			want: "invalid definition of Internal: duplicate detail code: E0001",
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "errors.json")
+			if !assert.NoError(t, os.WriteFile(path, []byte(tc.content), 0o644)) {
+				return
+			}
+
+			c, err := loadCatalog(path)
+			if tc.want == "" {
+				assert.NoError(t, err)
+				assert.Len(t, c.Errors, 1)
+				return
+			}
+			assert.EqualError(t, err, tc.want)
+		})
+	}
+}
+
+func assertFile(t *testing.T, golden, got string) {
+	t.Helper()
+	want, err := os.ReadFile(golden)
+	if !assert.NoError(t, err) {
+		return
+	}
+	b, err := os.ReadFile(got)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, string(want), string(b))
+}
@@ -48,12 +48,16 @@ func (s *assertingPingService) Ping(ctx context.Context, ping *pb_testproto.Ping
 }
 
 func TestRequestLogTestSuite(t *testing.T) {
+	logger, err := applog.NewBasicLogger(io.Discard)
+	if err != nil {
+		t.Fatalf("error occurred in NewBasicLogger: %v", err)
+	}
 	s := &RequestLogTestSuite{
 		InterceptorTestSuite: &grpc_testing.InterceptorTestSuite{
 			TestService: &assertingPingService{&grpc_testing.TestPingService{T: t}, t},
 			ServerOpts: []grpc.ServerOption{
 				grpc.UnaryInterceptor(grpc_context.UnaryServerInterceptor(
-					applog.NewBasicLogger(io.Discard),
+					logger,
 					grpc_context.RequestIDKey(requestIDKey),
 					grpc_context.AuthorizationKey(authorizationKey),
 				)),
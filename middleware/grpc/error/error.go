@@ -6,36 +6,56 @@ import (
 	"context"
 
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 
 	"github.com/takuoki/golib/apperr"
 	"github.com/takuoki/golib/applog"
+	"github.com/takuoki/golib/middleware/internal/errorutil"
 )
 
 // UnaryServerInterceptor returns a gRPC middleware that converts standard error to gRPC error.
-func UnaryServerInterceptor(domain, internalServerErrorCode string, logger applog.Logger) grpc.UnaryServerInterceptor {
+func UnaryServerInterceptor(domain, internalServerErrorCode string, logger applog.Logger, opt ...Option) grpc.UnaryServerInterceptor {
+
+	opts := defaultOptions
+	for _, o := range opt {
+		o.apply(&opts)
+	}
+
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
 		resp, err := handler(ctx, req)
 		if err != nil {
 			e, ok := apperr.Extract(err)
 			if !ok {
-				e = newInternalServerError(internalServerErrorCode, err)
+				e = errorutil.NewInternalServerError(internalServerErrorCode, err)
 			}
-			if e.Log() != "" {
-				logger.Error(ctx, e.Log())
+			errorutil.Log(ctx, logger, e)
+			errorutil.Notify(ctx, opts.notifier, logger, e)
+
+			if opts.catalog != nil {
+				e = opts.catalog.Localize(e, acceptLanguage(ctx))
 			}
 
-			return nil, e.GRPCError(domain)
+			var grpcOpts []apperr.GRPCOption
+			if opts.upstreamChain {
+				grpcOpts = append(grpcOpts, apperr.UpstreamChainOption())
+			}
+			return nil, apperr.ToGRPCError(e, domain, grpcOpts...)
 		}
 		return resp, nil
 	}
 }
 
-func newInternalServerError(code string, err error) apperr.Err {
-	return apperr.NewServerError(
-		codes.Internal,
-		code,
-		"internal server error",
-		err.Error(),
-	)
+// acceptLanguage returns the Accept-Language sent by the client,
+// including the one forwarded by grpc-gateway.
+func acceptLanguage(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	for _, key := range []string{"accept-language", "grpcgateway-accept-language"} {
+		if v := md.Get(key); len(v) > 0 {
+			return v[0]
+		}
+	}
+	return ""
 }
@@ -13,11 +13,15 @@ import (
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 
+	"github.com/takuoki/golib/appctx"
 	"github.com/takuoki/golib/apperr"
 	"github.com/takuoki/golib/applog"
 	grpc_error "github.com/takuoki/golib/middleware/grpc/error"
+	"github.com/takuoki/golib/notice"
+	"github.com/takuoki/golib/notice/noticetest"
 )
 
 const (
@@ -58,6 +62,10 @@ func (s *assertingPingService) Ping(ctx context.Context, ping *pb_testproto.Ping
 
 func TestErrorHandlerTestSuite(t *testing.T) {
 	buf := &bytes.Buffer{}
+	logger, err := applog.NewBasicLogger(buf, applog.TimeFormatOption("15:04:05"))
+	if err != nil {
+		t.Fatalf("error occurred in NewBasicLogger: %v", err)
+	}
 	s := &ErrorHandlerTestSuite{
 		InterceptorTestSuite: &grpc_testing.InterceptorTestSuite{
 			TestService: &assertingPingService{&grpc_testing.TestPingService{T: t}, t},
@@ -66,7 +74,7 @@ func TestErrorHandlerTestSuite(t *testing.T) {
 					grpc_error.UnaryServerInterceptor(
 						domain,
 						internalServerErrorCode,
-						applog.NewBasicLogger(buf, applog.TimeFormatOption("15:04:05")),
+						logger,
 					),
 				),
 			},
@@ -144,3 +152,163 @@ func (s *ErrorHandlerTestSuite) TestUnary_GeneralErr() {
 	}
 	assert.Regexp(s.T(), `^{"time":"\d{2}:\d{2}:\d{2}","level":"ERROR","message":"this is general error"}`+"\n$", s.buf.String(), "log message doesn't match")
 }
+
+func TestUnaryServerInterceptorNotifier(t *testing.T) {
+	testcases := map[string]struct {
+		err        error
+		wantNotice string
+	}{
+		"success":       {err: nil},
+		"apperr-client": {err: apperr.NewClientError(apperrClientStatus, apperrClientCode, apperrClientMessage)},
+		"apperr-server": {
+			err:        apperr.NewServerError(apperrServerStatus, apperrServerCode, apperrServerMessage, apperrServerLog),
+			wantNotice: apperrServerLog,
+		},
+		"general-error": {err: errors.New(generalErrorMessage), wantNotice: generalErrorMessage},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			logger, err := applog.NewSimpleLogger(buf)
+			if err != nil {
+				t.Fatalf("error occurred in NewSimpleLogger: %v", err)
+			}
+			r := noticetest.NewRecorder()
+
+			interceptor := grpc_error.UnaryServerInterceptor(domain, internalServerErrorCode, logger, grpc_error.Notifier(r))
+			ctx := appctx.WithRequestID(context.Background(), "req-1")
+			_, _ = interceptor(ctx, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req interface{}) (interface{}, error) {
+				return nil, tc.err
+			})
+
+			if tc.wantNotice == "" {
+				assert.Empty(t, r.Calls(), "notification must be empty")
+				return
+			}
+			calls := r.CallsOf(notice.ErrorSeverity)
+			if assert.Len(t, calls, 1) {
+				assert.Equal(t, tc.wantNotice, calls[0].Err.Error())
+				assert.Equal(t, "req-1", appctx.RequestID(calls[0].Ctx))
+				_, ok := apperr.Extract(calls[0].Err)
+				assert.True(t, ok, "notified error must wrap apperr.Err")
+			}
+		})
+	}
+
+	t.Run("notification-error", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		logger, err := applog.NewSimpleLogger(buf)
+		if err != nil {
+			t.Fatalf("error occurred in NewSimpleLogger: %v", err)
+		}
+		r := noticetest.NewRecorder()
+		r.SetError(errors.New("unavailable"))
+
+		interceptor := grpc_error.UnaryServerInterceptor(domain, internalServerErrorCode, logger, grpc_error.Notifier(r))
+		_, _ = interceptor(context.Background(), nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, errors.New(generalErrorMessage)
+		})
+
+		assert.Equal(t, generalErrorMessage+"\nfailed to notify server error: unavailable\n", buf.String())
+	})
+	t.Run("nil-notifier", func(t *testing.T) {
+		logger, err := applog.NewSimpleLogger(&bytes.Buffer{})
+		if err != nil {
+			t.Fatalf("error occurred in NewSimpleLogger: %v", err)
+		}
+
+		interceptor := grpc_error.UnaryServerInterceptor(domain, internalServerErrorCode, logger, grpc_error.Notifier(nil))
+		assert.NotPanics(t, func() {
+			_, _ = interceptor(context.Background(), nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req interface{}) (interface{}, error) {
+				return nil, errors.New(generalErrorMessage)
+			})
+		})
+	})
+}
+
+func TestUnaryServerInterceptorCatalog(t *testing.T) {
+	catalog := apperr.NewCatalog().MustAdd(apperrClientCode, "ja", "クライアントエラー")
+
+	testcases := map[string]struct {
+		md   metadata.MD
+		want *apperr.LocalizedMessage
+	}{
+		"accept-language":             {md: metadata.Pairs("accept-language", "ja-JP"), want: &apperr.LocalizedMessage{Locale: "ja", Message: "クライアントエラー"}},
+		"grpcgateway-accept-language": {md: metadata.Pairs("grpcgateway-accept-language", "ja"), want: &apperr.LocalizedMessage{Locale: "ja", Message: "クライアントエラー"}},
+		"fallback":                    {md: metadata.Pairs("accept-language", "en")},
+		"no-metadata":                 {},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			logger, err := applog.NewSimpleLogger(&bytes.Buffer{})
+			if err != nil {
+				t.Fatalf("error occurred in NewSimpleLogger: %v", err)
+			}
+
+			interceptor := grpc_error.UnaryServerInterceptor(domain, internalServerErrorCode, logger, grpc_error.Catalog(catalog))
+			ctx := context.Background()
+			if tc.md != nil {
+				ctx = metadata.NewIncomingContext(ctx, tc.md)
+			}
+			_, err = interceptor(ctx, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req interface{}) (interface{}, error) {
+				return nil, apperr.NewClientError(apperrClientStatus, apperrClientCode, apperrClientMessage)
+			})
+
+			e, ok := apperr.ExtractFromGRPCError(err)
+			if assert.True(t, ok, "gRPC error must be extracted") {
+				assert.Equal(t, apperrClientMessage, e.Message(), "message must be the default message")
+				assert.Equal(t, tc.want, e.(apperr.Detailer).Details().LocalizedMessage)
+			}
+		})
+	}
+}
+
+func TestUnaryServerInterceptorStackTrace(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger, err := applog.NewSimpleLogger(buf)
+	if err != nil {
+		t.Fatalf("error occurred in NewSimpleLogger: %v", err)
+	}
+
+	interceptor := grpc_error.UnaryServerInterceptor(domain, internalServerErrorCode, logger)
+	_, _ = interceptor(context.Background(), nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, apperr.NewServerError(apperrServerStatus, apperrServerCode, apperrServerMessage, apperrServerLog, apperr.StackTraceOption())
+	})
+
+	assert.Regexp(t, `^`+apperrServerLog+` \(stack_trace: github.com/takuoki/golib/middleware/grpc/error_test.TestUnaryServerInterceptorStackTrace.func1\n\t.*/error_test.go:\d+\n`, buf.String())
+}
+
+func TestUnaryServerInterceptorUpstreamChain(t *testing.T) {
+	upstreamErr := apperr.NewClientError(apperrClientStatus, apperrClientCode, apperrClientMessage).GRPCError("upstream.domain")
+
+	testcases := map[string]struct {
+		opts []grpc_error.Option
+		want []string
+	}{
+		"default":        {want: []string{domain}},
+		"upstream-chain": {opts: []grpc_error.Option{grpc_error.UpstreamChain()}, want: []string{"upstream.domain", domain}},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			logger, err := applog.NewSimpleLogger(&bytes.Buffer{})
+			if err != nil {
+				t.Fatalf("error occurred in NewSimpleLogger: %v", err)
+			}
+
+			interceptor := grpc_error.UnaryServerInterceptor(domain, internalServerErrorCode, logger, tc.opts...)
+			_, err = interceptor(context.Background(), nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req interface{}) (interface{}, error) {
+				e, _ := apperr.ExtractFromGRPCError(upstreamErr)
+				return nil, e
+			})
+
+			e, ok := apperr.ExtractFromGRPCError(err)
+			if assert.True(t, ok, "gRPC error must be extracted") {
+				assert.Equal(t, domain, e.(apperr.Originator).Domain())
+				assert.Equal(t, tc.want, e.(apperr.Originator).Upstream())
+			}
+		})
+	}
+}
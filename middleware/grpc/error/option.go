@@ -0,0 +1,73 @@
+package grpc_error
+
+import (
+	"github.com/takuoki/golib/apperr"
+	"github.com/takuoki/golib/notice"
+)
+
+type options struct {
+	notifier notice.ContextNotifier
+	catalog  *apperr.Catalog
+
+	upstreamChain bool
+}
+
+var defaultOptions = options{
+	notifier: nil,
+	catalog:  nil,
+
+	upstreamChain: false,
+}
+
+// Option is an option when creating middleware.
+type Option interface {
+	apply(*options)
+}
+
+type funcOption struct {
+	f func(*options)
+}
+
+func (fdo *funcOption) apply(do *options) {
+	fdo.f(do)
+}
+
+func newFuncOption(f func(*options)) *funcOption {
+	return &funcOption{
+		f: f,
+	}
+}
+
+// Notifier is an option to notify server errors with Error of the notifier.
+// The message of the notified error is the log of the server error.
+// The default does not notify, and a nil notifier also disables the notification.
+func Notifier(n notice.Notifier) Option {
+	return newFuncOption(func(o *options) {
+		if n == nil {
+			o.notifier = nil
+			return
+		}
+		o.notifier = notice.NewContextNotifier(n)
+	})
+}
+
+// Catalog is an option to localize error messages with the catalog
+// according to the gRPC metadata "accept-language"
+// (or "grpcgateway-accept-language" forwarded by grpc-gateway).
+// The localized message is returned as errdetails.LocalizedMessage in addition to the default message.
+// The default does not localize.
+func Catalog(c *apperr.Catalog) Option {
+	return newFuncOption(func(o *options) {
+		o.catalog = c
+	})
+}
+
+// UpstreamChain is an option to include the upstream domains in the gRPC error
+// when re-raising the error extracted from a gRPC error of another service.
+// See apperr.UpstreamChainOption for details.
+// The default does not include.
+func UpstreamChain() Option {
+	return newFuncOption(func(o *options) {
+		o.upstreamChain = true
+	})
+}
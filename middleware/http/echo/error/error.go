@@ -5,7 +5,9 @@ package echo_error
 import (
 	"context"
 	"fmt"
-	"net/http"
+	"math"
+	"strconv"
+	"time"
 
 	echo "github.com/labstack/echo/v4"
 	"google.golang.org/grpc/codes"
@@ -13,37 +15,50 @@ import (
 	"github.com/takuoki/golib/appctx/echoctx"
 	"github.com/takuoki/golib/apperr"
 	"github.com/takuoki/golib/applog"
+	"github.com/takuoki/golib/middleware/internal/errorutil"
 )
 
 // Middleware returns a echo middleware that converts standard error to HTTP error.
-func Middleware(internalServerErrorCode string, logger applog.Logger) echo.MiddlewareFunc {
+func Middleware(internalServerErrorCode string, logger applog.Logger, opt ...Option) echo.MiddlewareFunc {
+
+	opts := defaultOptions
+	for _, o := range opt {
+		o.apply(&opts)
+	}
+
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
 			ctx := echoctx.New(c).GetContext()
 			if err := next(c); err != nil {
+				var status int
 				e, ok := apperr.Extract(err)
 				if !ok {
 					if herr, ok := err.(*echo.HTTPError); ok {
 						e = apperr.NewClientError(
-							codeFromHTTPStatus(ctx, herr.Code, logger),
-							"-",
+							codeFromHTTPStatus(ctx, herr.Code, opts.statusCodes, logger),
+							apperr.NoDetailCode,
 							fmt.Sprintf("%v", herr.Message),
 						)
+						// Keep the status of echo.HTTPError (ex. 405) that cannot be restored from the gRPC code.
+						status = herr.Code
 					} else {
-						e = newInternalServerError(internalServerErrorCode, err)
+						e = errorutil.NewInternalServerError(internalServerErrorCode, err)
 					}
 				}
-				if e.Log() != "" {
-					logger.Error(ctx, e.Log())
+				errorutil.Log(ctx, logger, e)
+				errorutil.Notify(ctx, opts.notifier, logger, e)
+
+				if opts.catalog != nil {
+					e = opts.catalog.Localize(e, c.Request().Header.Get("Accept-Language"))
 				}
 
-				return c.JSON(e.HTTPStatus(), struct {
-					Code    string `json:"code"`
-					Message string `json:"message"`
-				}{
-					Code:    e.DetailCode(),
-					Message: e.Message(),
-				})
+				if status == 0 {
+					status = e.HTTPStatus()
+				}
+				if r, ok := e.(apperr.Retrier); ok && r.RetryDelay() > 0 {
+					c.Response().Header().Set(echo.HeaderRetryAfter, retryAfter(r.RetryDelay()))
+				}
+				return opts.render(c, status, e)
 			}
 
 			return nil
@@ -51,41 +66,19 @@ func Middleware(internalServerErrorCode string, logger applog.Logger) echo.Middl
 	}
 }
 
-func newInternalServerError(code string, err error) apperr.Err {
-	return apperr.NewServerError(
-		codes.Internal,
-		code,
-		"internal server error",
-		err.Error(),
-	)
+// retryAfter returns the value of Retry-After header in seconds, rounded up.
+func retryAfter(d time.Duration) string {
+	return strconv.FormatInt(int64(math.Ceil(d.Seconds())), 10)
 }
 
-func codeFromHTTPStatus(ctx context.Context, status int, logger applog.Logger) codes.Code {
-	switch status {
-	case http.StatusOK, http.StatusCreated, http.StatusAccepted, http.StatusNoContent:
-		return codes.OK
-	case http.StatusRequestTimeout:
-		return codes.Canceled
-	case http.StatusBadRequest:
-		return codes.InvalidArgument
-	case http.StatusGatewayTimeout:
-		return codes.DeadlineExceeded
-	case http.StatusNotFound:
-		return codes.NotFound
-	case http.StatusConflict:
-		return codes.AlreadyExists
-	case http.StatusForbidden:
-		return codes.PermissionDenied
-	case http.StatusUnauthorized:
-		return codes.Unauthenticated
-	case http.StatusTooManyRequests:
-		return codes.ResourceExhausted
-	case http.StatusNotImplemented:
-		return codes.Unimplemented
-	case http.StatusInternalServerError:
-		return codes.Internal
-	case http.StatusServiceUnavailable:
-		return codes.Unavailable
+// codeFromHTTPStatus converts HTTP status to gRPC code.
+// `table` takes precedence over apperr.CodeFromHTTPStatus.
+func codeFromHTTPStatus(ctx context.Context, status int, table map[int]codes.Code, logger applog.Logger) codes.Code {
+	if code, ok := table[status]; ok {
+		return code
+	}
+	if code, ok := apperr.CodeFromHTTPStatus(status); ok {
+		return code
 	}
 
 	logger.Warnf(ctx, "unknown HTTP status: %d", status)
@@ -8,14 +8,19 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	echo "github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
 	"google.golang.org/grpc/codes"
 
+	"github.com/takuoki/golib/appctx"
+	"github.com/takuoki/golib/appctx/echoctx"
 	"github.com/takuoki/golib/apperr"
 	"github.com/takuoki/golib/applog"
 	echo_error "github.com/takuoki/golib/middleware/http/echo/error"
+	"github.com/takuoki/golib/notice"
+	"github.com/takuoki/golib/notice/noticetest"
 )
 
 func TestMiddleware(t *testing.T) {
@@ -27,6 +32,7 @@ func TestMiddleware(t *testing.T) {
 		wantStatus int
 		wantResp   string
 		wantLog    string
+		wantNotice string
 	}{
 		"success": {
 			err:        nil,
@@ -36,18 +42,72 @@ func TestMiddleware(t *testing.T) {
 		"client error": {
 			err:        apperr.NewClientError(codes.InvalidArgument, "C0001", "client error"),
 			wantStatus: 400,
-			wantResp:   `{"code":"C0001","message":"client error"}` + "\n",
+			wantResp:   `{"code":"C0001","grpc_code":"InvalidArgument","message":"client error"}` + "\n",
+		},
+		"client error with details": {
+			err: apperr.NewClientError(codes.InvalidArgument, "C0001", "client error",
+				apperr.FieldViolationOption("name", "REQUIRED", "name is required"),
+				apperr.MetadataOption(map[string]string{"key": "value"}),
+				apperr.LocalizedMessageOption("ja-JP", "クライアントエラー"),
+				apperr.HelpLinkOption("docs", "https://example.com"),
+			),
+			wantStatus: 400,
+			wantResp: `{"code":"C0001","grpc_code":"InvalidArgument","message":"client error",` +
+				`"violations":[{"field":"name","reason":"REQUIRED","description":"name is required"}],` +
+				`"metadata":{"key":"value"},` +
+				`"localized_message":{"locale":"ja-JP","message":"クライアントエラー"},` +
+				`"help_links":[{"description":"docs","url":"https://example.com"}]}` + "\n",
+		},
+		"validation error": {
+			err: apperr.NewValidation("C0100", "invalid request").
+				AddFieldViolation("name", "C0101", "name is required").
+				AddFieldViolation("age", "C0102", "age must be positive").
+				Err(),
+			wantStatus: 400,
+			wantResp: `{"code":"C0100","grpc_code":"InvalidArgument","message":"invalid request","violations":[` +
+				`{"field":"name","reason":"C0101","description":"name is required"},` +
+				`{"field":"age","reason":"C0102","description":"age must be positive"}]}` + "\n",
+		},
+		"multi error": {
+			err: apperr.Join(
+				apperr.NewClientError(codes.InvalidArgument, "C0002", "invalid argument"),
+				apperr.NewClientError(codes.NotFound, "C0001", "not found"),
+			),
+			wantStatus: 404,
+			wantResp: `{"code":"C0001","grpc_code":"NotFound","message":"not found","errors":[` +
+				`{"code":"C0002","grpc_code":"InvalidArgument","message":"invalid argument"},` +
+				`{"code":"C0001","grpc_code":"NotFound","message":"not found"}]}` + "\n",
+		},
+		"client error with quota": {
+			err: apperr.NewClientError(codes.ResourceExhausted, "C0002", "quota exceeded",
+				apperr.QuotaViolationOption("user:1", "limit exceeded"),
+				apperr.PreconditionViolationOption("TOS", "user:1", "not accepted"),
+				apperr.RetryDelayOption(1500*time.Millisecond),
+			),
+			wantStatus: 429,
+			wantResp: `{"code":"C0002","grpc_code":"ResourceExhausted","message":"quota exceeded",` +
+				`"quota_violations":[{"subject":"user:1","description":"limit exceeded"}],` +
+				`"precondition_violations":[{"type":"TOS","subject":"user:1","description":"not accepted"}],` +
+				`"retry_delay":1.5}` + "\n",
 		},
 		"echo http error": {
 			err:        echo.ErrNotFound,
 			wantStatus: 404,
-			wantResp:   `{"code":"-","message":"Not Found"}` + "\n",
+			wantResp:   `{"code":"-","grpc_code":"NotFound","message":"Not Found"}` + "\n",
 		},
 		"server error": {
 			err:        errors.New("server error"),
 			wantStatus: 500,
-			wantResp:   fmt.Sprintf(`{"code":"%s","message":"internal server error"}`+"\n", internalServerErrorCode),
+			wantResp:   fmt.Sprintf(`{"code":"%s","grpc_code":"Internal","message":"internal server error"}`+"\n", internalServerErrorCode),
 			wantLog:    "server error\n",
+			wantNotice: "server error",
+		},
+		"apperr server error": {
+			err:        apperr.NewServerError(codes.Unavailable, "S0002", "service unavailable", "db is down"),
+			wantStatus: 503,
+			wantResp:   `{"code":"S0002","grpc_code":"Unavailable","message":"service unavailable"}` + "\n",
+			wantLog:    "db is down\n",
+			wantNotice: "db is down",
 		},
 	}
 
@@ -63,7 +123,8 @@ func TestMiddleware(t *testing.T) {
 				t.Fatalf("error occurred in NewSimpleLogger: %v", err)
 			}
 
-			m := echo_error.Middleware(internalServerErrorCode, logger)
+			r := noticetest.NewRecorder()
+			m := echo_error.Middleware(internalServerErrorCode, logger, echo_error.Notifier(r))
 			h := m(func(c echo.Context) error {
 				if tc.err != nil {
 					return tc.err
@@ -86,13 +147,376 @@ func TestMiddleware(t *testing.T) {
 			} else {
 				assert.Equal(t, tc.wantLog, buf.String(), "log doesn't match")
 			}
+
+			if tc.wantNotice == "" {
+				assert.Empty(t, r.Calls(), "notification must be empty")
+			} else if calls := r.CallsOf(notice.ErrorSeverity); assert.Len(t, calls, 1) {
+				assert.Equal(t, tc.wantNotice, calls[0].Err.Error(), "notification doesn't match")
+			}
+		})
+	}
+}
+
+func TestMiddleware_NilNotifier(t *testing.T) {
+	logger, err := applog.NewSimpleLogger(&bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("error occurred in NewSimpleLogger: %v", err)
+	}
+
+	m := echo_error.Middleware("S0001", logger, echo_error.Notifier(nil))
+	h := m(func(c echo.Context) error {
+		return errors.New("db is down")
+	})
+
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+	assert.NotPanics(t, func() {
+		assert.NoError(t, h(c))
+	})
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
+
+func TestMiddleware_Catalog(t *testing.T) {
+	catalog := apperr.NewCatalog().MustAdd("C0001", "ja", "クライアントエラー")
+
+	testcases := map[string]struct {
+		acceptLanguage string
+		wantResp       string
+	}{
+		"localized": {
+			acceptLanguage: "ja-JP,en;q=0.8",
+			wantResp:       `{"code":"C0001","grpc_code":"InvalidArgument","message":"client error","localized_message":{"locale":"ja","message":"クライアントエラー"}}` + "\n",
+		},
+		"fallback": {
+			acceptLanguage: "en",
+			wantResp:       `{"code":"C0001","grpc_code":"InvalidArgument","message":"client error"}` + "\n",
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			logger, err := applog.NewSimpleLogger(&bytes.Buffer{})
+			if err != nil {
+				t.Fatalf("error occurred in NewSimpleLogger: %v", err)
+			}
+
+			m := echo_error.Middleware("S0001", logger, echo_error.Catalog(catalog))
+			h := m(func(c echo.Context) error {
+				return apperr.NewClientError(codes.InvalidArgument, "C0001", "client error")
+			})
+
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Accept-Language", tc.acceptLanguage)
+			c := echo.New().NewContext(req, rec)
+
+			assert.NoError(t, h(c))
+			assert.Equal(t, tc.wantResp, rec.Body.String())
+		})
+	}
+}
+
+func TestMiddleware_Format(t *testing.T) {
+	validationErr := apperr.NewValidation("C0100", "invalid request").
+		AddFieldViolation("name", "C0101", "name is required").
+		Err()
+
+	testcases := map[string]struct {
+		opts            []echo_error.Option
+		err             error
+		wantStatus      int
+		wantContentType string
+		wantResp        string
+	}{
+		"json": {
+			opts:            []echo_error.Option{echo_error.Format(echo_error.JSONFormat)},
+			err:             apperr.NewClientError(codes.NotFound, "C0001", "not found"),
+			wantStatus:      404,
+			wantContentType: "application/json",
+			wantResp:        `{"code":"C0001","grpc_code":"NotFound","message":"not found"}` + "\n",
+		},
+		"problem-details": {
+			opts: []echo_error.Option{
+				echo_error.Format(echo_error.ProblemDetailsFormat),
+				echo_error.ProblemTypeBaseURI("https://example.com/errors/"),
+			},
+			err:             validationErr,
+			wantStatus:      400,
+			wantContentType: "application/problem+json",
+			wantResp: `{"type":"https://example.com/errors/C0100","title":"Bad Request","status":400,` +
+				`"detail":"invalid request","instance":"req-1","code":"C0100","grpc_code":"InvalidArgument",` +
+				`"violations":[{"field":"name","reason":"C0101","description":"name is required"}]}`,
+		},
+		"problem-details-about-blank": {
+			opts:            []echo_error.Option{echo_error.Format(echo_error.ProblemDetailsFormat)},
+			err:             errors.New("server error"),
+			wantStatus:      500,
+			wantContentType: "application/problem+json",
+			wantResp: `{"type":"about:blank","title":"Internal Server Error","status":500,` +
+				`"detail":"internal server error","instance":"req-1","code":"S0001","grpc_code":"Internal"}`,
+		},
+		"problem-details-no-detail-code": {
+			opts: []echo_error.Option{
+				echo_error.Format(echo_error.ProblemDetailsFormat),
+				echo_error.ProblemTypeBaseURI("https://example.com/errors/"),
+			},
+			err:             echo.ErrMethodNotAllowed,
+			wantStatus:      405,
+			wantContentType: "application/problem+json",
+			wantResp: `{"type":"about:blank","title":"Method Not Allowed","status":405,` +
+				`"detail":"Method Not Allowed","instance":"req-1","code":"-","grpc_code":"Unimplemented"}`,
+		},
+		"renderer": {
+			opts: []echo_error.Option{
+				echo_error.Format(echo_error.ProblemDetailsFormat),
+				echo_error.Renderer(func(c echo.Context, status int, e apperr.Err) error {
+					return c.String(status, e.DetailCode()+": "+e.Message())
+				}),
+			},
+			err:             apperr.NewClientError(codes.NotFound, "C0001", "not found"),
+			wantStatus:      404,
+			wantContentType: "text/plain",
+			wantResp:        "C0001: not found",
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			logger, err := applog.NewSimpleLogger(&bytes.Buffer{})
+			if err != nil {
+				t.Fatalf("error occurred in NewSimpleLogger: %v", err)
+			}
+
+			m := echo_error.Middleware("S0001", logger, tc.opts...)
+			h := m(func(c echo.Context) error {
+				return tc.err
+			})
+
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			c := echoctx.New(echo.New().NewContext(req, rec))
+			c.SetContext(appctx.WithRequestID(c.GetContext(), "req-1"))
+
+			assert.NoError(t, h(c))
+			assert.Equal(t, tc.wantStatus, rec.Code)
+			assert.Contains(t, rec.Header().Get(echo.HeaderContentType), tc.wantContentType)
+			assert.Equal(t, tc.wantResp, rec.Body.String())
+		})
+	}
+}
+
+func TestMiddleware_HTTPError(t *testing.T) {
+	testcases := map[string]struct {
+		opts       []echo_error.Option
+		err        error
+		wantStatus int
+		wantResp   string
+		wantLog    string
+	}{
+		"method not allowed": {
+			err:        echo.ErrMethodNotAllowed,
+			wantStatus: 405,
+			wantResp:   `{"code":"-","grpc_code":"Unimplemented","message":"Method Not Allowed"}` + "\n",
+		},
+		"unknown status": {
+			err:        echo.NewHTTPError(http.StatusTeapot, "teapot"),
+			wantStatus: 418,
+			wantResp:   `{"code":"-","grpc_code":"Internal","message":"teapot"}` + "\n",
+			wantLog:    "unknown HTTP status: 418\n",
+		},
+		"status code option": {
+			opts: []echo_error.Option{
+				echo_error.HTTPStatusCode(http.StatusTeapot, codes.Unimplemented),
+				echo_error.Body(func(c echo.Context, e apperr.Err) interface{} {
+					return map[string]string{"grpc_code": e.Code().String()}
+				}),
+			},
+			err:        echo.NewHTTPError(http.StatusTeapot, "teapot"),
+			wantStatus: 418,
+			wantResp:   `{"grpc_code":"Unimplemented"}` + "\n",
+		},
+		"body option": {
+			opts: []echo_error.Option{echo_error.Body(func(c echo.Context, e apperr.Err) interface{} {
+				return map[string]string{"error": e.DetailCode(), "grpc_code": e.Code().String()}
+			})},
+			err:        apperr.NewClientError(codes.NotFound, "C0001", "not found"),
+			wantStatus: 404,
+			wantResp:   `{"error":"C0001","grpc_code":"NotFound"}` + "\n",
+		},
+		"body option with problem details": {
+			opts: []echo_error.Option{
+				echo_error.Format(echo_error.ProblemDetailsFormat),
+				echo_error.Body(func(c echo.Context, e apperr.Err) interface{} {
+					return map[string]string{"title": e.Message()}
+				}),
+			},
+			err:        apperr.NewClientError(codes.NotFound, "C0001", "not found"),
+			wantStatus: 404,
+			wantResp:   `{"title":"not found"}`,
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			logger, err := applog.NewSimpleLogger(buf)
+			if err != nil {
+				t.Fatalf("error occurred in NewSimpleLogger: %v", err)
+			}
+
+			m := echo_error.Middleware("S0001", logger, tc.opts...)
+			h := m(func(c echo.Context) error {
+				return tc.err
+			})
+
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			c := echo.New().NewContext(req, rec)
+
+			assert.NoError(t, h(c))
+			assert.Equal(t, tc.wantStatus, rec.Code)
+			assert.Equal(t, tc.wantResp, rec.Body.String())
+			assert.Equal(t, tc.wantLog, buf.String())
+		})
+	}
+}
+
+func TestMiddleware_RetryAfter(t *testing.T) {
+	testcases := map[string]struct {
+		err  error
+		want string
+	}{
+		"seconds":    {err: apperr.NewServerError(codes.Unavailable, "S0002", "unavailable", "log", apperr.RetryDelayOption(30*time.Second)), want: "30"},
+		"round-up":   {err: apperr.NewClientError(codes.ResourceExhausted, "C0002", "too many requests", apperr.RetryDelayOption(1500*time.Millisecond)), want: "2"},
+		"no-delay":   {err: apperr.NewServerError(codes.Unavailable, "S0002", "unavailable", "log")},
+		"not-apperr": {err: errors.New("error")},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			logger, err := applog.NewSimpleLogger(&bytes.Buffer{})
+			if err != nil {
+				t.Fatalf("error occurred in NewSimpleLogger: %v", err)
+			}
+
+			m := echo_error.Middleware("S0001", logger)
+			h := m(func(c echo.Context) error {
+				return tc.err
+			})
+
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			c := echo.New().NewContext(req, rec)
+
+			assert.NoError(t, h(c))
+			assert.Equal(t, tc.want, rec.Header().Get(echo.HeaderRetryAfter))
 		})
 	}
 }
 
+func TestMiddleware_StackTrace(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger, err := applog.NewSimpleLogger(buf)
+	if err != nil {
+		t.Fatalf("error occurred in NewSimpleLogger: %v", err)
+	}
+
+	m := echo_error.Middleware("S0001", logger)
+	h := m(func(c echo.Context) error {
+		return apperr.NewServerError(codes.Internal, "S0002", "internal server error", "db is down", apperr.StackTraceOption())
+	})
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	c := echo.New().NewContext(req, rec)
+
+	assert.NoError(t, h(c))
+	assert.Regexp(t, `^db is down \(stack_trace: github.com/takuoki/golib/middleware/http/echo/error_test.TestMiddleware_StackTrace.func1\n\t.*/error_test.go:\d+\n`, buf.String())
+}
+
+func TestMiddleware_ExtractFromHTTPResponse(t *testing.T) {
+	want := apperr.NewValidation("C0100", "invalid request").
+		AddFieldViolation("name", "C0101", "name is required").
+		Err()
+
+	testcases := map[string]struct {
+		opts []echo_error.Option
+	}{
+		"json":            {},
+		"problem-details": {opts: []echo_error.Option{echo_error.Format(echo_error.ProblemDetailsFormat)}},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			logger, err := applog.NewSimpleLogger(&bytes.Buffer{})
+			if err != nil {
+				t.Fatalf("error occurred in NewSimpleLogger: %v", err)
+			}
+
+			m := echo_error.Middleware("S0001", logger, tc.opts...)
+			h := m(func(c echo.Context) error {
+				return want
+			})
+
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			assert.NoError(t, h(echo.New().NewContext(req, rec)))
+
+			e, ok := apperr.ExtractFromHTTPResponse(rec.Result())
+			if assert.True(t, ok, "apperr.Err must be extracted") {
+				assert.True(t, errors.Is(e, want), "errors.Is is not true")
+				assert.Equal(t, want.Type(), e.Type())
+				assert.Equal(t, want.Message(), e.Message())
+				assert.Equal(t, want.(apperr.Detailer).Details(), e.(apperr.Detailer).Details())
+			}
+		})
+	}
+}
+
+func TestMiddleware_ExtractFromHTTPResponse_AllCodes(t *testing.T) {
+	logger, err := applog.NewSimpleLogger(&bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("error occurred in NewSimpleLogger: %v", err)
+	}
+
+	formats := map[string]echo_error.ResponseFormat{
+		"json":            echo_error.JSONFormat,
+		"problem-details": echo_error.ProblemDetailsFormat,
+	}
+	for name, format := range formats {
+		for code := codes.Canceled; code <= codes.Unauthenticated; code++ {
+			t.Run(name+"/"+code.String(), func(t *testing.T) {
+				var want apperr.Err
+				if apperr.DefaultClassifier(code) == apperr.ServerError {
+					want = apperr.NewServerError(code, "E0001", "error", "log")
+				} else {
+					want = apperr.NewClientError(code, "E0001", "error")
+				}
+
+				m := echo_error.Middleware("S0001", logger, echo_error.Format(format))
+				h := m(func(c echo.Context) error {
+					return want
+				})
+
+				rec := httptest.NewRecorder()
+				req := httptest.NewRequest(http.MethodGet, "/", nil)
+				assert.NoError(t, h(echo.New().NewContext(req, rec)))
+
+				e, ok := apperr.ExtractFromHTTPResponse(rec.Result())
+				if assert.True(t, ok, "apperr.Err must be extracted") {
+					assert.Equal(t, want.Code(), e.Code(), "Code is not equal.")
+					assert.Equal(t, want.Type(), e.Type(), "Type is not equal.")
+					assert.True(t, errors.Is(e, want), "errors.Is is not true")
+				}
+			})
+		}
+	}
+}
+
 func TestCodeFromHTTPStatus(t *testing.T) {
 	testcases := map[string]struct {
 		in      int
+		table   map[int]codes.Code
 		want    codes.Code
 		wantLog string
 	}{
@@ -108,6 +532,14 @@ func TestCodeFromHTTPStatus(t *testing.T) {
 		"not implemented":       {in: http.StatusNotImplemented, want: codes.Unimplemented},
 		"internal server error": {in: http.StatusInternalServerError, want: codes.Internal},
 		"service unavailable":   {in: http.StatusServiceUnavailable, want: codes.Unavailable},
+		"method not allowed":    {in: http.StatusMethodNotAllowed, want: codes.Unimplemented},
+		"precondition failed":   {in: http.StatusPreconditionFailed, want: codes.FailedPrecondition},
+		"entity too large":      {in: http.StatusRequestEntityTooLarge, want: codes.InvalidArgument},
+		"unsupported media":     {in: http.StatusUnsupportedMediaType, want: codes.InvalidArgument},
+		"unprocessable entity":  {in: http.StatusUnprocessableEntity, want: codes.InvalidArgument},
+		"bad gateway":           {in: http.StatusBadGateway, want: codes.Unavailable},
+		"table":                 {in: http.StatusTeapot, table: map[int]codes.Code{http.StatusTeapot: codes.Aborted}, want: codes.Aborted},
+		"table-override":        {in: http.StatusNotFound, table: map[int]codes.Code{http.StatusNotFound: codes.PermissionDenied}, want: codes.PermissionDenied},
 		"unknown":               {in: http.StatusTeapot, want: codes.Internal, wantLog: "unknown HTTP status: 418\n"},
 	}
 
@@ -121,7 +553,7 @@ func TestCodeFromHTTPStatus(t *testing.T) {
 				t.Fatalf("error occurred in NewSimpleLogger: %v", err)
 			}
 
-			r := echo_error.CodeFromHTTPStatus(context.Background(), tc.in, logger)
+			r := echo_error.CodeFromHTTPStatus(context.Background(), tc.in, tc.table, logger)
 
 			assert.Equal(t, tc.want, r)
 
@@ -0,0 +1,118 @@
+package echo_error
+
+import (
+	"google.golang.org/grpc/codes"
+
+	"github.com/takuoki/golib/apperr"
+	"github.com/takuoki/golib/notice"
+)
+
+type options struct {
+	notifier notice.ContextNotifier
+	catalog  *apperr.Catalog
+
+	format             ResponseFormat
+	problemTypeBaseURI string
+	renderFunc         RenderFunc
+	bodyFunc           BodyFunc
+
+	statusCodes map[int]codes.Code
+}
+
+var defaultOptions = options{
+	notifier: nil,
+	catalog:  nil,
+
+	format:             JSONFormat,
+	problemTypeBaseURI: "",
+	renderFunc:         nil,
+	bodyFunc:           nil,
+
+	statusCodes: nil,
+}
+
+// Option is an option when creating middleware.
+type Option interface {
+	apply(*options)
+}
+
+type funcOption struct {
+	f func(*options)
+}
+
+func (fdo *funcOption) apply(do *options) {
+	fdo.f(do)
+}
+
+func newFuncOption(f func(*options)) *funcOption {
+	return &funcOption{
+		f: f,
+	}
+}
+
+// Notifier is an option to notify server errors with Error of the notifier.
+// The message of the notified error is the log of the server error.
+// The default does not notify, and a nil notifier also disables the notification.
+func Notifier(n notice.Notifier) Option {
+	return newFuncOption(func(o *options) {
+		if n == nil {
+			o.notifier = nil
+			return
+		}
+		o.notifier = notice.NewContextNotifier(n)
+	})
+}
+
+// Catalog is an option to localize error messages with the catalog
+// according to the Accept-Language header.
+// The localized message is returned as errdetails.LocalizedMessage in addition to the default message.
+// The default does not localize.
+func Catalog(c *apperr.Catalog) Option {
+	return newFuncOption(func(o *options) {
+		o.catalog = c
+	})
+}
+
+// Format is an option to set the format of the error response.
+// The default is JSONFormat.
+func Format(f ResponseFormat) Option {
+	return newFuncOption(func(o *options) {
+		o.format = f
+	})
+}
+
+// ProblemTypeBaseURI is an option to set the base URI of the "type" member of ProblemDetailsFormat.
+// The "type" is the base URI followed by the detail code (ex. "https://example.com/errors/E0001").
+// The default is empty, and the "type" is "about:blank" as well as for the error without the detail code.
+func ProblemTypeBaseURI(uri string) Option {
+	return newFuncOption(func(o *options) {
+		o.problemTypeBaseURI = uri
+	})
+}
+
+// Renderer is an option to write the error response with the function instead of Format.
+func Renderer(fn RenderFunc) Option {
+	return newFuncOption(func(o *options) {
+		o.renderFunc = fn
+	})
+}
+
+// Body is an option to replace the body of the error response with the one returned by the function.
+// Unlike Renderer, the status and the content type follow the middleware.
+func Body(fn BodyFunc) Option {
+	return newFuncOption(func(o *options) {
+		o.bodyFunc = fn
+	})
+}
+
+// HTTPStatusCode is an option to add or override the conversion
+// from HTTP status of echo.HTTPError to gRPC code.
+// The HTTP status of the response is kept as it is regardless of the conversion.
+func HTTPStatusCode(status int, code codes.Code) Option {
+	return newFuncOption(func(o *options) {
+		if o.statusCodes == nil {
+			o.statusCodes = map[int]codes.Code{}
+		}
+		o.statusCodes[status] = code
+	})
+}
@@ -0,0 +1,144 @@
+package echo_error
+
+import (
+	"encoding/json"
+	"net/http"
+
+	echo "github.com/labstack/echo/v4"
+
+	"github.com/takuoki/golib/appctx"
+	"github.com/takuoki/golib/appctx/echoctx"
+	"github.com/takuoki/golib/apperr"
+)
+
+// ResponseFormat is the format of the error response.
+type ResponseFormat int
+
+// ResponseFormat list.
+const (
+	// JSONFormat is the JSON body of the code and the message with the error details.
+	// ex. {"code":"E0001","message":"not found"}
+	JSONFormat ResponseFormat = iota
+	// ProblemDetailsFormat is the application/problem+json body defined in RFC 9457.
+	ProblemDetailsFormat
+)
+
+// MIMEApplicationProblemJSON is the content type of ProblemDetailsFormat.
+const MIMEApplicationProblemJSON = apperr.MIMEApplicationProblemJSON
+
+// RenderFunc is a function that writes the error response.
+// `status` is the HTTP status code of the response.
+type RenderFunc func(c echo.Context, status int, e apperr.Err) error
+
+// BodyFunc is a function that returns the body of the error response.
+// The body is encoded to JSON with the content type of the response format.
+type BodyFunc func(c echo.Context, e apperr.Err) interface{}
+
+func (o *options) render(c echo.Context, status int, e apperr.Err) error {
+	if o.renderFunc != nil {
+		return o.renderFunc(c, status, e)
+	}
+
+	var body interface{}
+	switch {
+	case o.bodyFunc != nil:
+		body = o.bodyFunc(c, e)
+	case o.format == ProblemDetailsFormat:
+		body = newProblemDetails(c, status, e, o.problemTypeBaseURI)
+	default:
+		body = newResponse(e)
+	}
+
+	if o.format == ProblemDetailsFormat {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		return c.Blob(status, MIMEApplicationProblemJSON, b)
+	}
+	return c.JSON(status, body)
+}
+
+// response is the JSON body of the error.
+// The error details are included only when they exist.
+type response struct {
+	Code string `json:"code"`
+	// GRPCCode is the gRPC code so that the client can restore the error (see apperr.ExtractFromHTTPResponse).
+	GRPCCode string `json:"grpc_code"`
+	Message  string `json:"message"`
+	apperr.Details
+	// RetryDelay is the retry delay in seconds.
+	RetryDelay float64 `json:"retry_delay,omitempty"`
+	// Errors is the aggregated errors of apperr.MultiErr.
+	Errors []response `json:"errors,omitempty"`
+}
+
+func newResponse(e apperr.Err) response {
+	d := details(e)
+	return response{
+		Code:       e.DetailCode(),
+		GRPCCode:   e.Code().String(),
+		Message:    e.Message(),
+		Details:    d,
+		RetryDelay: d.RetryDelay.Seconds(),
+		Errors:     newItemResponses(e),
+	}
+}
+
+// newItemResponses returns the responses of the aggregated errors, or nil if the error is not aggregated.
+func newItemResponses(e apperr.Err) []response {
+	me, ok := e.(apperr.MultiErr)
+	if !ok {
+		return nil
+	}
+	items := make([]response, 0, len(me.Errors()))
+	for _, item := range me.Errors() {
+		items = append(items, newResponse(item))
+	}
+	return items
+}
+
+// problemDetails is the body of RFC 9457 Problem Details.
+// The detail code and the error details are added as extension members.
+type problemDetails struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail"`
+	Instance string `json:"instance,omitempty"`
+	Code     string `json:"code"`
+	GRPCCode string `json:"grpc_code"`
+	apperr.Details
+	// RetryDelay is the retry delay in seconds.
+	RetryDelay float64 `json:"retry_delay,omitempty"`
+	// Errors is the aggregated errors of apperr.MultiErr.
+	Errors []response `json:"errors,omitempty"`
+}
+
+func newProblemDetails(c echo.Context, status int, e apperr.Err, typeBaseURI string) problemDetails {
+	typ := "about:blank"
+	if typeBaseURI != "" && e.DetailCode() != "" && e.DetailCode() != apperr.NoDetailCode {
+		typ = typeBaseURI + e.DetailCode()
+	}
+	d := details(e)
+	return problemDetails{
+		Type:       typ,
+		Title:      http.StatusText(status),
+		Status:     status,
+		Detail:     e.Message(),
+		Instance:   appctx.RequestID(echoctx.New(c).GetContext()),
+		Code:       e.DetailCode(),
+		GRPCCode:   e.Code().String(),
+		Details:    d,
+		RetryDelay: d.RetryDelay.Seconds(),
+		Errors:     newItemResponses(e),
+	}
+}
+
+// details returns the details of the error, or the zero value if the error has no details.
+func details(e apperr.Err) apperr.Details {
+	if d, ok := e.(apperr.Detailer); ok {
+		return d.Details()
+	}
+	return apperr.Details{}
+}
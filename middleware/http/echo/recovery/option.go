@@ -2,14 +2,18 @@ package echo_recovery
 
 import (
 	"context"
+
+	"github.com/takuoki/golib/notice"
 )
 
 type options struct {
 	recoveryFunc func(ctx context.Context, p interface{}) (err error)
+	notifier     notice.ContextNotifier
 }
 
 var defaultOptions = options{
 	recoveryFunc: nil,
+	notifier:     nil,
 }
 
 // Option is an option when creating middleware.
@@ -46,3 +50,16 @@ func RecoveryContextFunc(fn func(ctx context.Context, p interface{}) (err error)
 		o.recoveryFunc = fn
 	})
 }
+
+// Notifier is an option to notify recovered panics with Critical of the notifier.
+// The notified error is the error returned by the recovery function.
+// The default does not notify, and a nil notifier also disables the notification.
+func Notifier(n notice.Notifier) Option {
+	return newFuncOption(func(o *options) {
+		if n == nil {
+			o.notifier = nil
+			return
+		}
+		o.notifier = notice.NewContextNotifier(n)
+	})
+}
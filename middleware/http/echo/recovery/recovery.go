@@ -23,7 +23,16 @@ func Middleware(opt ...Option) echo.MiddlewareFunc {
 		return func(c echo.Context) (err error) {
 			defer func() {
 				if r := recover(); r != nil {
-					err = recoverFrom(echoctx.New(c).GetContext(), r, opts.recoveryFunc)
+					ctx := echoctx.New(c).GetContext()
+					err = recoverFrom(ctx, r, opts.recoveryFunc)
+					if opts.notifier != nil {
+						nerr := err
+						if nerr == nil {
+							nerr = fmt.Errorf("panic recovered: %v", r)
+						}
+						// The notification error is ignored because this middleware has no logger.
+						_ = opts.notifier.CriticalContext(ctx, nerr)
+					}
 				}
 			}()
 
@@ -10,8 +10,11 @@ import (
 	echo "github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/takuoki/golib/appctx"
 	"github.com/takuoki/golib/appctx/echoctx"
 	echo_recovery "github.com/takuoki/golib/middleware/http/echo/recovery"
+	"github.com/takuoki/golib/notice"
+	"github.com/takuoki/golib/notice/noticetest"
 )
 
 // nolint:staticcheck
@@ -80,3 +83,66 @@ func TestMiddleware(t *testing.T) {
 		})
 	}
 }
+
+func TestMiddlewareNotifier(t *testing.T) {
+
+	testcases := map[string]struct {
+		opts       []echo_recovery.Option
+		wantNotice string
+	}{
+		"recover": {
+			opts: []echo_recovery.Option{
+				echo_recovery.RecoveryFunc(func(p interface{}) (err error) {
+					return fmt.Errorf("panic recovered: %v", p)
+				}),
+			},
+			wantNotice: "panic recovered: panic",
+		},
+		"recover nil": {
+			opts: []echo_recovery.Option{
+				echo_recovery.RecoveryFunc(func(p interface{}) (err error) {
+					return nil
+				}),
+			},
+			wantNotice: "panic recovered: panic",
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			e := echo.New()
+			r := noticetest.NewRecorder()
+			m := echo_recovery.Middleware(append(tc.opts, echo_recovery.Notifier(r))...)
+
+			h := m(func(c echo.Context) error {
+				panic("panic")
+			})
+
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			ec := echoctx.New(e.NewContext(req, rec))
+			ec.SetContext(appctx.WithRequestID(ec.GetContext(), "req-1"))
+
+			_ = h(ec)
+
+			calls := r.CallsOf(notice.CriticalSeverity)
+			if assert.Len(t, calls, 1) {
+				assert.Equal(t, tc.wantNotice, calls[0].Err.Error())
+				assert.Equal(t, "req-1", appctx.RequestID(calls[0].Ctx))
+			}
+		})
+	}
+
+	t.Run("nil-notifier", func(t *testing.T) {
+		m := echo_recovery.Middleware(echo_recovery.Notifier(nil))
+		h := m(func(c echo.Context) error {
+			panic("panic")
+		})
+
+		rec := httptest.NewRecorder()
+		c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+		assert.NotPanics(t, func() {
+			_ = h(c)
+		})
+	})
+}
@@ -0,0 +1,63 @@
+// Package errorutil provides the error handling shared by the error middlewares.
+package errorutil
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+
+	"github.com/takuoki/golib/apperr"
+	"github.com/takuoki/golib/applog"
+	"github.com/takuoki/golib/notice"
+)
+
+// NewInternalServerError returns the server error for the error that is not apperr.Err.
+func NewInternalServerError(code string, err error) apperr.Err {
+	return apperr.WrapServerError(
+		err,
+		codes.Internal,
+		code,
+		"internal server error",
+		"",
+	)
+}
+
+// Log outputs the log of the error with the stack trace if captured.
+func Log(ctx context.Context, logger applog.Logger, e apperr.Err) {
+	if e.Log() == "" {
+		return
+	}
+	var labels map[string]string
+	if st, ok := e.(apperr.StackTracer); ok && st.StackTrace() != nil {
+		labels = map[string]string{"stack_trace": st.StackTrace().String()}
+	}
+	logger.Print(ctx, applog.ErrorLevel, e.Log(), labels)
+}
+
+// Notify notifies the server error with Error of the notifier.
+// It does nothing for the client error or if the notifier is nil.
+// If the notification fails, it is output to `logger` as a warning.
+func Notify(ctx context.Context, n notice.ContextNotifier, logger applog.Logger, e apperr.Err) {
+	if n == nil || e.Type() != apperr.ServerError {
+		return
+	}
+	if err := n.ErrorContext(ctx, notification{e}); err != nil {
+		logger.Warnf(ctx, "failed to notify server error: %v", err)
+	}
+}
+
+// notification is a server error to notify, whose message is the log of the server error.
+type notification struct {
+	apperr.Err
+}
+
+func (n notification) Error() string {
+	if l := n.Log(); l != "" {
+		return l
+	}
+	return n.Message()
+}
+
+func (n notification) Unwrap() error {
+	return n.Err
+}
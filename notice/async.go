@@ -0,0 +1,388 @@
+package notice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Errors reported by AsyncNotifier.
+var (
+	ErrClosed      = errors.New("notifier is closed")
+	ErrQueueFull   = errors.New("notification queue is full")
+	ErrRateLimited = errors.New("notification is rate limited")
+)
+
+const (
+	defaultGroupWindow = 10 * time.Second
+	defaultQueueSize   = 100
+)
+
+// AsyncNotifier is a notifier that sends notifications in the background.
+// The first occurrence of an error is sent immediately, and the same errors notified
+// within the group window after it are sent as one follow-up notification.
+// The number of notifications can be limited per severity.
+// Call Flush or Close before the application exits so that pending notifications are not lost.
+type AsyncNotifier struct {
+	n            ContextNotifier
+	window       time.Duration
+	fingerprint  func(err error) string
+	errorHandler func(err error)
+	limiters     map[Severity]*rateLimiter
+
+	mu     sync.Mutex
+	groups map[string]*group
+
+	closeMu     sync.RWMutex
+	closed      bool
+	queueClosed bool
+	queueSize   int
+	queue       chan *group
+	done        chan struct{}
+}
+
+type group struct {
+	key      string
+	ctx      context.Context
+	severity Severity
+	err      error
+	// count is the number of the occurrences after the first one.
+	count int
+	timer *time.Timer
+
+	// flushed is set only for the marker of Flush, and closed when the marker is processed.
+	flushed chan struct{}
+}
+
+// AsyncOption is an option for NewAsyncNotifier.
+type AsyncOption func(*AsyncNotifier)
+
+// AsyncGroupWindow sets the window in which the same errors after the first one
+// are grouped into one follow-up notification.
+// The window starts at the first error, which is sent without waiting for the window.
+// The default is 10 seconds. Specify 0 to send each error without grouping.
+func AsyncGroupWindow(d time.Duration) AsyncOption {
+	return func(a *AsyncNotifier) {
+		a.window = d
+	}
+}
+
+// AsyncFingerprint sets the function that returns the key to group the same errors.
+// The default is DefaultFingerprint.
+func AsyncFingerprint(fn func(err error) string) AsyncOption {
+	return func(a *AsyncNotifier) {
+		a.fingerprint = fn
+	}
+}
+
+// AsyncRateLimit limits the number of notifications of the severity to `limit` per `interval`.
+// Notifications over the limit are dropped and reported to the error handler as ErrRateLimited.
+// The default is no limit, and both `limit` and `interval` must be positive.
+func AsyncRateLimit(s Severity, limit int, interval time.Duration) AsyncOption {
+	return func(a *AsyncNotifier) {
+		a.limiters[s] = &rateLimiter{limit: limit, interval: interval}
+	}
+}
+
+// AsyncErrorHandler sets the function called when a notification fails or is dropped.
+// The default ignores the error.
+func AsyncErrorHandler(fn func(err error)) AsyncOption {
+	return func(a *AsyncNotifier) {
+		a.errorHandler = fn
+	}
+}
+
+// AsyncQueueSize sets the number of notifications that can wait to be sent.
+// Notifications over the size are dropped and reported to the error handler as ErrQueueFull.
+// The default is 100, and the size must be positive.
+func AsyncQueueSize(size int) AsyncOption {
+	return func(a *AsyncNotifier) {
+		a.queueSize = size
+	}
+}
+
+// NewAsyncNotifier returns a notifier that sends notifications with `n` in the background.
+func NewAsyncNotifier(n Notifier, opts ...AsyncOption) (*AsyncNotifier, error) {
+	if n == nil {
+		return nil, errors.New("notifier is nil")
+	}
+	a := &AsyncNotifier{
+		n:            NewContextNotifier(n),
+		window:       defaultGroupWindow,
+		fingerprint:  DefaultFingerprint,
+		errorHandler: func(error) {},
+		limiters:     map[Severity]*rateLimiter{},
+		groups:       map[string]*group{},
+		queueSize:    defaultQueueSize,
+		done:         make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	if a.queueSize <= 0 {
+		return nil, fmt.Errorf("invalid queue size: %d", a.queueSize)
+	}
+	for s, l := range a.limiters {
+		if l.limit <= 0 {
+			return nil, fmt.Errorf("invalid rate limit of %s: %d", s, l.limit)
+		}
+		if l.interval <= 0 {
+			return nil, fmt.Errorf("invalid rate limit interval of %s: %s", s, l.interval)
+		}
+	}
+	a.queue = make(chan *group, a.queueSize)
+	go a.run()
+	return a, nil
+}
+
+// DefaultFingerprint returns the detail code for the error with the detail code
+// (ex. apperr.Err), and the error message for the other errors.
+func DefaultFingerprint(err error) string {
+	var dc interface{ DetailCode() string }
+	if errors.As(err, &dc) && dc.DetailCode() != "" {
+		return "detail_code:" + dc.DetailCode()
+	}
+	return "message:" + err.Error()
+}
+
+// GroupError is an error that represents the same errors grouped by AsyncNotifier.
+type GroupError struct {
+	// Err is the first error in the group.
+	Err error
+	// Count is the number of the occurrences after the first one, which has been notified already.
+	Count int
+}
+
+// Error returns the message of the first error with the number of the following occurrences.
+func (e *GroupError) Error() string {
+	if e.Count == 1 {
+		return fmt.Sprintf("%s (1 more occurrence)", e.Err.Error())
+	}
+	return fmt.Sprintf("%s (%d more occurrences)", e.Err.Error(), e.Count)
+}
+
+// Unwrap returns the first error in the group.
+func (e *GroupError) Unwrap() error {
+	return e.Err
+}
+
+// Error queues the error to be notified as an error.
+func (a *AsyncNotifier) Error(err error) error {
+	return a.enqueue(ContextFromError(err), ErrorSeverity, err)
+}
+
+// Critical queues the error to be notified as a critical error.
+func (a *AsyncNotifier) Critical(err error) error {
+	return a.enqueue(ContextFromError(err), CriticalSeverity, err)
+}
+
+// ErrorContext queues the error to be notified as an error with the context.
+func (a *AsyncNotifier) ErrorContext(ctx context.Context, err error) error {
+	return a.enqueue(ctx, ErrorSeverity, err)
+}
+
+// CriticalContext queues the error to be notified as a critical error with the context.
+func (a *AsyncNotifier) CriticalContext(ctx context.Context, err error) error {
+	return a.enqueue(ctx, CriticalSeverity, err)
+}
+
+// Flush sends all pending notifications without waiting for the group window,
+// and waits until they are sent or the context is done.
+func (a *AsyncNotifier) Flush(ctx context.Context) error {
+	a.mu.Lock()
+	groups := make([]*group, 0, len(a.groups))
+	for key, g := range a.groups {
+		g.timer.Stop()
+		delete(a.groups, key)
+		if g.count > 0 {
+			groups = append(groups, g)
+		}
+	}
+	a.mu.Unlock()
+
+	for _, g := range groups {
+		a.dispatch(g)
+	}
+
+	// The queue is processed in order, so all notifications dispatched above
+	// have been sent when the marker is processed.
+	marker := &group{flushed: make(chan struct{})}
+	a.closeMu.RLock()
+	if a.queueClosed {
+		a.closeMu.RUnlock()
+		return nil
+	}
+	select {
+	case a.queue <- marker:
+		a.closeMu.RUnlock()
+	case <-ctx.Done():
+		a.closeMu.RUnlock()
+		return ctx.Err()
+	}
+
+	select {
+	case <-marker.flushed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close flushes pending notifications and stops the background process.
+// Notifications after Close return ErrClosed.
+func (a *AsyncNotifier) Close(ctx context.Context) error {
+	a.closeMu.Lock()
+	if a.closed {
+		a.closeMu.Unlock()
+		return nil
+	}
+	a.closed = true
+	a.closeMu.Unlock()
+
+	err := a.Flush(ctx)
+
+	a.closeMu.Lock()
+	a.queueClosed = true
+	close(a.queue)
+	a.closeMu.Unlock()
+
+	select {
+	case <-a.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return err
+}
+
+func (a *AsyncNotifier) enqueue(ctx context.Context, s Severity, err error) error {
+	a.closeMu.RLock()
+	closed := a.closed
+	a.closeMu.RUnlock()
+	if closed {
+		return ErrClosed
+	}
+
+	first := &group{
+		ctx:      context.WithoutCancel(ctx),
+		severity: s,
+		err:      err,
+	}
+	if a.window <= 0 {
+		a.dispatch(first)
+		return nil
+	}
+
+	key := s.String() + ":" + a.fingerprint(err)
+	a.mu.Lock()
+	if exist, ok := a.groups[key]; ok {
+		exist.count++
+		a.mu.Unlock()
+		return nil
+	}
+	g := &group{
+		key:      key,
+		ctx:      first.ctx,
+		severity: s,
+		err:      err,
+	}
+	g.timer = time.AfterFunc(a.window, func() { a.release(g) })
+	a.groups[key] = g
+	a.mu.Unlock()
+
+	a.dispatch(first)
+	return nil
+}
+
+// release dispatches the group whose window has passed if the error occurred again in the window.
+// It does nothing if the group has already been flushed.
+func (a *AsyncNotifier) release(g *group) {
+	a.mu.Lock()
+	ok := a.groups[g.key] == g
+	if ok {
+		delete(a.groups, g.key)
+	}
+	a.mu.Unlock()
+
+	if ok && g.count > 0 {
+		a.dispatch(g)
+	}
+}
+
+// dispatch queues the group to be sent.
+// The error handler is called after releasing the lock, so that it can call Close.
+func (a *AsyncNotifier) dispatch(g *group) {
+	if err := a.tryQueue(g); err != nil {
+		a.errorHandler(fmt.Errorf("%w: %v", err, g.err))
+	}
+}
+
+func (a *AsyncNotifier) tryQueue(g *group) error {
+	a.closeMu.RLock()
+	defer a.closeMu.RUnlock()
+
+	if a.queueClosed {
+		return ErrClosed
+	}
+	select {
+	case a.queue <- g:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+func (a *AsyncNotifier) run() {
+	defer close(a.done)
+	for g := range a.queue {
+		if g.flushed != nil {
+			close(g.flushed)
+			continue
+		}
+		a.send(g)
+	}
+}
+
+func (a *AsyncNotifier) send(g *group) {
+	if l, ok := a.limiters[g.severity]; ok && !l.allow(time.Now()) {
+		a.errorHandler(fmt.Errorf("%w: %v", ErrRateLimited, g.err))
+		return
+	}
+
+	err := g.err
+	if g.count > 0 {
+		err = &GroupError{Err: g.err, Count: g.count}
+	}
+
+	var nerr error
+	if g.severity == CriticalSeverity {
+		nerr = a.n.CriticalContext(g.ctx, err)
+	} else {
+		nerr = a.n.ErrorContext(g.ctx, err)
+	}
+	if nerr != nil {
+		a.errorHandler(nerr)
+	}
+}
+
+// rateLimiter allows `limit` events per `interval` in a fixed window.
+// It is used only by the background goroutine.
+type rateLimiter struct {
+	limit    int
+	interval time.Duration
+	start    time.Time
+	count    int
+}
+
+func (l *rateLimiter) allow(t time.Time) bool {
+	if t.Sub(l.start) >= l.interval {
+		l.start = t
+		l.count = 0
+	}
+	if l.count >= l.limit {
+		return false
+	}
+	l.count++
+	return true
+}
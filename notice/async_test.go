@@ -0,0 +1,296 @@
+package notice_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+
+	"github.com/takuoki/golib/appctx"
+	"github.com/takuoki/golib/apperr"
+	"github.com/takuoki/golib/notice"
+)
+
+type sent struct {
+	ctx      context.Context
+	severity notice.Severity
+	err      error
+}
+
+type syncNotifier struct {
+	mu   sync.Mutex
+	sent []sent
+	err  error
+}
+
+func (n *syncNotifier) ErrorContext(ctx context.Context, err error) error {
+	return n.record(ctx, notice.ErrorSeverity, err)
+}
+
+func (n *syncNotifier) CriticalContext(ctx context.Context, err error) error {
+	return n.record(ctx, notice.CriticalSeverity, err)
+}
+
+func (n *syncNotifier) Error(err error) error {
+	return n.ErrorContext(notice.ContextFromError(err), err)
+}
+
+func (n *syncNotifier) Critical(err error) error {
+	return n.CriticalContext(notice.ContextFromError(err), err)
+}
+
+func (n *syncNotifier) record(ctx context.Context, s notice.Severity, err error) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.sent = append(n.sent, sent{ctx: ctx, severity: s, err: err})
+	return n.err
+}
+
+func (n *syncNotifier) messages() []string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	msgs := make([]string, len(n.sent))
+	for i, s := range n.sent {
+		msgs[i] = s.severity.String() + ": " + s.err.Error()
+	}
+	sort.Strings(msgs)
+	return msgs
+}
+
+// blockingNotifier blocks notifications until release is closed.
+type blockingNotifier struct {
+	syncNotifier
+	release chan struct{}
+}
+
+func (n *blockingNotifier) ErrorContext(ctx context.Context, err error) error {
+	<-n.release
+	return n.syncNotifier.ErrorContext(ctx, err)
+}
+
+func (n *blockingNotifier) Error(err error) error {
+	return n.ErrorContext(notice.ContextFromError(err), err)
+}
+
+func TestAsyncNotifier(t *testing.T) {
+	t.Run("group", func(t *testing.T) {
+		n := &syncNotifier{}
+		a := newAsyncNotifier(t, n, notice.AsyncGroupWindow(time.Hour))
+
+		ctx, cancel := context.WithCancel(appctx.WithRequestID(context.Background(), "req-1"))
+		assert.Nil(t, a.ErrorContext(ctx, errors.New("error message")))
+		cancel()
+		assert.Nil(t, a.Error(errors.New("error message")))
+		assert.Nil(t, a.Error(errors.New("error message")))
+		assert.Nil(t, a.Critical(errors.New("error message")))
+		assert.Nil(t, a.Error(apperr.NewClientError(codes.NotFound, "E0001", "user not found")))
+		assert.Nil(t, a.Error(apperr.NewClientError(codes.NotFound, "E0001", "item not found")))
+		assert.Eventually(t, func() bool {
+			return len(n.messages()) == 3
+		}, time.Second, 5*time.Millisecond, "first occurrences must not wait for the window")
+		assert.Equal(t, []string{
+			"critical: error message",
+			"error: error message",
+			"error: user not found",
+		}, n.messages())
+
+		assert.Nil(t, a.Flush(context.Background()))
+		assert.Equal(t, []string{
+			"critical: error message",
+			"error: error message",
+			"error: error message (2 more occurrences)",
+			"error: user not found",
+			"error: user not found (1 more occurrence)",
+		}, n.messages())
+
+		for _, s := range n.sent {
+			if s.err.Error() == "error message (2 more occurrences)" {
+				var ge *notice.GroupError
+				if assert.True(t, errors.As(s.err, &ge)) {
+					assert.Equal(t, 2, ge.Count)
+				}
+				assert.Equal(t, "req-1", appctx.RequestID(s.ctx))
+				assert.Nil(t, s.ctx.Err(), "context must not be canceled")
+			}
+		}
+	})
+	t.Run("window", func(t *testing.T) {
+		n := &syncNotifier{}
+		a := newAsyncNotifier(t, n, notice.AsyncGroupWindow(10*time.Millisecond))
+
+		assert.Nil(t, a.Error(errors.New("error message")))
+		assert.Nil(t, a.Error(errors.New("error message")))
+		assert.Nil(t, a.Error(errors.New("another message")))
+		assert.Eventually(t, func() bool {
+			return len(n.messages()) == 3
+		}, time.Second, 5*time.Millisecond)
+		assert.Equal(t, []string{
+			"error: another message",
+			"error: error message",
+			"error: error message (1 more occurrence)",
+		}, n.messages())
+	})
+	t.Run("rate-limit", func(t *testing.T) {
+		n := &syncNotifier{}
+		var mu sync.Mutex
+		errs := []error{}
+		a := newAsyncNotifier(
+			t,
+			n,
+			notice.AsyncGroupWindow(0),
+			notice.AsyncRateLimit(notice.ErrorSeverity, 2, time.Hour),
+			notice.AsyncErrorHandler(func(err error) {
+				mu.Lock()
+				defer mu.Unlock()
+				errs = append(errs, err)
+			}),
+		)
+
+		for _, msg := range []string{"error1", "error2", "error3"} {
+			assert.Nil(t, a.Error(errors.New(msg)))
+		}
+		assert.Nil(t, a.Critical(errors.New("critical")))
+		assert.Nil(t, a.Flush(context.Background()))
+
+		assert.Equal(t, []string{"critical: critical", "error: error1", "error: error2"}, n.messages())
+		if assert.Len(t, errs, 1) {
+			assert.ErrorIs(t, errs[0], notice.ErrRateLimited)
+			assert.Equal(t, "notification is rate limited: error3", errs[0].Error())
+		}
+	})
+	t.Run("notification-error", func(t *testing.T) {
+		n := &syncNotifier{err: errors.New("unavailable")}
+		errs := make(chan error, 1)
+		a := newAsyncNotifier(t, n, notice.AsyncErrorHandler(func(err error) { errs <- err }))
+
+		assert.Nil(t, a.Error(errors.New("error message")))
+		assert.Nil(t, a.Flush(context.Background()))
+		assert.Equal(t, "unavailable", (<-errs).Error())
+	})
+	t.Run("close-in-error-handler", func(t *testing.T) {
+		n := &blockingNotifier{release: make(chan struct{})}
+		var (
+			a      *notice.AsyncNotifier
+			once   sync.Once
+			closed = make(chan error, 1)
+		)
+		a = newAsyncNotifier(t, n,
+			notice.AsyncGroupWindow(0),
+			notice.AsyncQueueSize(1),
+			notice.AsyncErrorHandler(func(err error) {
+				once.Do(func() {
+					close(n.release)
+					closed <- a.Close(context.Background())
+				})
+			}),
+		)
+
+		// The notifier blocks, so the queue becomes full and ErrQueueFull is reported.
+		for i := 0; i < 3; i++ {
+			_ = a.Error(errors.New("error message"))
+		}
+		select {
+		case err := <-closed:
+			assert.Nil(t, err)
+		case <-time.After(time.Second):
+			t.Fatal("Close in the error handler must not deadlock")
+		}
+	})
+	t.Run("close", func(t *testing.T) {
+		n := &syncNotifier{}
+		a := newAsyncNotifier(t, n)
+
+		assert.Nil(t, a.Error(errors.New("error message")))
+		assert.Nil(t, a.Close(context.Background()))
+		assert.Equal(t, []string{"error: error message"}, n.messages())
+
+		assert.ErrorIs(t, a.Error(errors.New("error message")), notice.ErrClosed)
+		assert.Nil(t, a.Flush(context.Background()))
+		assert.Nil(t, a.Close(context.Background()))
+	})
+}
+
+func TestNewAsyncNotifierError(t *testing.T) {
+	testcases := map[string]struct {
+		n    notice.Notifier
+		opts []notice.AsyncOption
+		want string
+	}{
+		"nil-notifier": {
+			n:    nil,
+			want: "notifier is nil",
+		},
+		"zero-queue-size": {
+			n:    &syncNotifier{},
+			opts: []notice.AsyncOption{notice.AsyncQueueSize(0)},
+			want: "invalid queue size: 0",
+		},
+		"zero-rate-limit": {
+			n:    &syncNotifier{},
+			opts: []notice.AsyncOption{notice.AsyncRateLimit(notice.CriticalSeverity, 0, time.Minute)},
+			want: "invalid rate limit of critical: 0",
+		},
+		"zero-rate-limit-interval": {
+			n:    &syncNotifier{},
+			opts: []notice.AsyncOption{notice.AsyncRateLimit(notice.ErrorSeverity, 1, 0)},
+			want: "invalid rate limit interval of error: 0s",
+		},
+		"negative-queue-size": {
+			n:    &syncNotifier{},
+			opts: []notice.AsyncOption{notice.AsyncQueueSize(-1)},
+			want: "invalid queue size: -1",
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			_, err := notice.NewAsyncNotifier(tc.n, tc.opts...)
+			assert.EqualError(t, err, tc.want)
+		})
+	}
+}
+
+func TestDefaultFingerprint(t *testing.T) {
+	testcases := map[string]struct {
+		err  error
+		want string
+	}{
+		"apperr": {
+			err:  apperr.NewClientError(codes.NotFound, "E0001", "user not found"),
+			want: "detail_code:E0001",
+		},
+		"wrapped-apperr": {
+			err:  fmt.Errorf("wrapped: %w", apperr.NewClientError(codes.NotFound, "E0001", "user not found")),
+			want: "detail_code:E0001",
+		},
+		"empty-detail-code": {
+			err:  apperr.NewServerError(codes.Internal, "", "internal error", "db down"),
+			want: "message:internal error",
+		},
+		"other": {
+			err:  errors.New("error message"),
+			want: "message:error message",
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.want, notice.DefaultFingerprint(tc.err))
+		})
+	}
+}
+
+func newAsyncNotifier(t *testing.T, n notice.Notifier, opts ...notice.AsyncOption) *notice.AsyncNotifier {
+	t.Helper()
+	a, err := notice.NewAsyncNotifier(n, opts...)
+	if err != nil {
+		t.Fatalf("error occurred in NewAsyncNotifier: %v", err)
+	}
+	return a
+}
@@ -0,0 +1,96 @@
+package notice
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+const (
+	defaultTimeout       = 5 * time.Second
+	defaultRetry         = 2
+	defaultRetryInterval = time.Second
+)
+
+// httpSender sends a request body to the URL with retries.
+type httpSender struct {
+	client        *http.Client
+	timeout       time.Duration
+	retry         int
+	retryInterval time.Duration
+}
+
+func newHTTPSender() httpSender {
+	return httpSender{
+		client:        http.DefaultClient,
+		timeout:       defaultTimeout,
+		retry:         defaultRetry,
+		retryInterval: defaultRetryInterval,
+	}
+}
+
+// validate reports an error if the timeout or the number of retries is negative.
+func (s *httpSender) validate() error {
+	if s.timeout < 0 {
+		return fmt.Errorf("invalid timeout: %s", s.timeout)
+	}
+	if s.retry < 0 {
+		return fmt.Errorf("invalid retry: %d", s.retry)
+	}
+	return nil
+}
+
+// send posts the body to the URL.
+// It retries when the request fails or the server returns 429 or 5xx status.
+// The context is not expected to be canceled (see context.WithoutCancel),
+// so that the notification is not lost when the request that caused the error finishes.
+func (s *httpSender) send(ctx context.Context, url string, header http.Header, body []byte) error {
+	var err error
+	for i := 0; i <= s.retry; i++ {
+		if i > 0 {
+			time.Sleep(s.retryInterval)
+		}
+		var retryable bool
+		retryable, err = s.post(ctx, url, header, body)
+		if err == nil || !retryable {
+			return err
+		}
+	}
+	return err
+}
+
+func (s *httpSender) post(ctx context.Context, url string, header http.Header, body []byte) (retryable bool, err error) {
+	if s.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.timeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("failed to create request: %w", err)
+	}
+	for k, vs := range header {
+		for _, v := range vs {
+			req.Header.Add(k, v)
+		}
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return true, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return false, nil
+	}
+
+	b, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	retryable = resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retryable, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, bytes.TrimSpace(b))
+}
@@ -0,0 +1,116 @@
+package notice
+
+import (
+	"context"
+	"errors"
+)
+
+type multiNotifier struct {
+	notifiers []ContextNotifier
+}
+
+// NewMultiNotifier returns a notifier that notifies with all the notifiers in order.
+// Even if some notifiers fail, it notifies with the rest,
+// and returns the errors joined by errors.Join.
+// It also implements ContextNotifier.
+func NewMultiNotifier(notifiers ...Notifier) Notifier {
+	return newMultiNotifier(notifiers)
+}
+
+func newMultiNotifier(notifiers []Notifier) *multiNotifier {
+	ns := make([]ContextNotifier, 0, len(notifiers))
+	for _, n := range notifiers {
+		if n != nil {
+			ns = append(ns, NewContextNotifier(n))
+		}
+	}
+	return &multiNotifier{notifiers: ns}
+}
+
+// Error notifies the error with all the notifiers as an error.
+func (n *multiNotifier) Error(err error) error {
+	return n.ErrorContext(ContextFromError(err), err)
+}
+
+// Critical notifies the error with all the notifiers as a critical error.
+func (n *multiNotifier) Critical(err error) error {
+	return n.CriticalContext(ContextFromError(err), err)
+}
+
+// ErrorContext notifies the error with all the notifiers as an error with the context.
+func (n *multiNotifier) ErrorContext(ctx context.Context, err error) error {
+	errs := make([]error, 0, len(n.notifiers))
+	for _, cn := range n.notifiers {
+		errs = append(errs, cn.ErrorContext(ctx, err))
+	}
+	return errors.Join(errs...)
+}
+
+// CriticalContext notifies the error with all the notifiers as a critical error with the context.
+func (n *multiNotifier) CriticalContext(ctx context.Context, err error) error {
+	errs := make([]error, 0, len(n.notifiers))
+	for _, cn := range n.notifiers {
+		errs = append(errs, cn.CriticalContext(ctx, err))
+	}
+	return errors.Join(errs...)
+}
+
+type routeNotifier struct {
+	errorNotifiers    []Notifier
+	criticalNotifiers []Notifier
+
+	error    *multiNotifier
+	critical *multiNotifier
+}
+
+// RouteOption is an option for NewRouteNotifier.
+type RouteOption func(*routeNotifier)
+
+// RouteError adds the notifiers that Error is dispatched to.
+func RouteError(notifiers ...Notifier) RouteOption {
+	return func(r *routeNotifier) {
+		r.errorNotifiers = append(r.errorNotifiers, notifiers...)
+	}
+}
+
+// RouteCritical adds the notifiers that Critical is dispatched to.
+func RouteCritical(notifiers ...Notifier) RouteOption {
+	return func(r *routeNotifier) {
+		r.criticalNotifiers = append(r.criticalNotifiers, notifiers...)
+	}
+}
+
+// NewRouteNotifier returns a notifier that dispatches Error and Critical
+// to the notifiers specified by RouteError and RouteCritical respectively.
+// A severity without notifiers is not notified.
+// The notifiers of each severity behave as NewMultiNotifier.
+// It also implements ContextNotifier.
+func NewRouteNotifier(opts ...RouteOption) Notifier {
+	r := &routeNotifier{}
+	for _, opt := range opts {
+		opt(r)
+	}
+	r.error = newMultiNotifier(r.errorNotifiers)
+	r.critical = newMultiNotifier(r.criticalNotifiers)
+	return r
+}
+
+// Error notifies the error with the notifiers for Error.
+func (n *routeNotifier) Error(err error) error {
+	return n.error.Error(err)
+}
+
+// Critical notifies the error with the notifiers for Critical.
+func (n *routeNotifier) Critical(err error) error {
+	return n.critical.Critical(err)
+}
+
+// ErrorContext notifies the error with the notifiers for Error with the context.
+func (n *routeNotifier) ErrorContext(ctx context.Context, err error) error {
+	return n.error.ErrorContext(ctx, err)
+}
+
+// CriticalContext notifies the error with the notifiers for Critical with the context.
+func (n *routeNotifier) CriticalContext(ctx context.Context, err error) error {
+	return n.critical.CriticalContext(ctx, err)
+}
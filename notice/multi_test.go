@@ -0,0 +1,65 @@
+package notice_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/takuoki/golib/appctx"
+	"github.com/takuoki/golib/notice"
+)
+
+func TestMultiNotifier(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		n1, n2 := &syncNotifier{}, &syncNotifier{}
+		n := notice.NewMultiNotifier(n1, nil, n2)
+
+		assert.Nil(t, n.Error(errors.New("error")))
+		assert.Nil(t, n.Critical(errors.New("critical")))
+		assert.Equal(t, []string{"critical: critical", "error: error"}, n1.messages())
+		assert.Equal(t, []string{"critical: critical", "error: error"}, n2.messages())
+	})
+	t.Run("partial-failure", func(t *testing.T) {
+		n1 := &syncNotifier{err: errors.New("n1 failed")}
+		n2 := &syncNotifier{}
+		n3 := &syncNotifier{err: errors.New("n3 failed")}
+		n := notice.NewMultiNotifier(n1, n2, n3)
+
+		err := n.Error(errors.New("error"))
+		if assert.NotNil(t, err) {
+			assert.Equal(t, "n1 failed\nn3 failed", err.Error())
+			assert.ErrorIs(t, err, n3.err)
+		}
+		assert.Equal(t, []string{"error: error"}, n2.messages())
+		assert.Equal(t, []string{"error: error"}, n3.messages())
+	})
+	t.Run("context", func(t *testing.T) {
+		n1 := &syncNotifier{}
+		n := notice.NewContextNotifier(notice.NewMultiNotifier(n1, notice.NewNopNotifier()))
+
+		ctx := appctx.WithRequestID(context.Background(), "req-1")
+		assert.Nil(t, n.CriticalContext(ctx, errors.New("critical")))
+		if assert.Len(t, n1.sent, 1) {
+			assert.Equal(t, "req-1", appctx.RequestID(n1.sent[0].ctx))
+		}
+	})
+}
+
+func TestRouteNotifier(t *testing.T) {
+	slack, pager := &syncNotifier{}, &syncNotifier{}
+	n := notice.NewRouteNotifier(
+		notice.RouteError(slack),
+		notice.RouteCritical(pager, slack),
+	)
+
+	assert.Nil(t, n.Error(errors.New("error")))
+	assert.Nil(t, notice.NewContextNotifier(n).CriticalContext(context.Background(), errors.New("critical")))
+	assert.Equal(t, []string{"critical: critical", "error: error"}, slack.messages())
+	assert.Equal(t, []string{"critical: critical"}, pager.messages())
+
+	t.Run("no-route", func(t *testing.T) {
+		n := notice.NewRouteNotifier(notice.RouteCritical(pager))
+		assert.Nil(t, n.Error(errors.New("error")))
+	})
+}
@@ -1,8 +1,11 @@
 package notice
 
+import "context"
+
 type nopNotifier struct{}
 
 // NewNopNotifier returns a notifier that does not notify anything.
+// It also implements ContextNotifier.
 func NewNopNotifier() Notifier {
 	return &nopNotifier{}
 }
@@ -16,3 +19,13 @@ func (*nopNotifier) Error(err error) error {
 func (*nopNotifier) Critical(err error) error {
 	return nil
 }
+
+// ErrorContext does nothing.
+func (*nopNotifier) ErrorContext(ctx context.Context, err error) error {
+	return nil
+}
+
+// CriticalContext does nothing.
+func (*nopNotifier) CriticalContext(ctx context.Context, err error) error {
+	return nil
+}
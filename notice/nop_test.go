@@ -1,6 +1,7 @@
 package notice_test
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -13,3 +14,11 @@ func TestNopNotifier(t *testing.T) {
 	assert.Nil(t, n.Error(errors.New("error")), "response of Error must be nil")
 	assert.Nil(t, n.Critical(errors.New("critical")), "response of Critical must be nil")
 }
+
+func TestNopContextNotifier(t *testing.T) {
+	n, ok := notice.NewNopNotifier().(notice.ContextNotifier)
+	if assert.True(t, ok, "nop notifier must implement ContextNotifier") {
+		assert.Nil(t, n.ErrorContext(context.Background(), errors.New("error")), "response of ErrorContext must be nil")
+		assert.Nil(t, n.CriticalContext(context.Background(), errors.New("critical")), "response of CriticalContext must be nil")
+	}
+}
@@ -2,9 +2,108 @@
 // It also defines an implementation for each notification destination.
 package notice
 
+import (
+	"context"
+	"errors"
+)
+
 // Notifier represents a notification interface
 // that sends a message to each destination.
 type Notifier interface {
 	Error(err error) error
 	Critical(err error) error
 }
+
+// ContextNotifier represents a notification interface that includes
+// the request scope data held in the context (ex. request ID, user ID) in the notification.
+type ContextNotifier interface {
+	ErrorContext(ctx context.Context, err error) error
+	CriticalContext(ctx context.Context, err error) error
+}
+
+// NewContextNotifier returns a ContextNotifier that notifies with the notifier.
+// If the notifier already implements ContextNotifier, it is returned as is.
+// Otherwise, the context is attached to the error with WithContext
+// and the error is passed to Error or Critical of the notifier.
+func NewContextNotifier(n Notifier) ContextNotifier {
+	if cn, ok := n.(ContextNotifier); ok {
+		return cn
+	}
+	return &contextNotifier{n: n}
+}
+
+type contextNotifier struct {
+	n Notifier
+}
+
+// ErrorContext calls Error of the notifier with the error that the context is attached.
+func (n *contextNotifier) ErrorContext(ctx context.Context, err error) error {
+	return n.n.Error(WithContext(ctx, err))
+}
+
+// CriticalContext calls Critical of the notifier with the error that the context is attached.
+func (n *contextNotifier) CriticalContext(ctx context.Context, err error) error {
+	return n.n.Critical(WithContext(ctx, err))
+}
+
+// Severity is the severity of a notification.
+type Severity int
+
+// Severity list.
+const (
+	ErrorSeverity Severity = iota + 1
+	CriticalSeverity
+)
+
+// String returns a string of the severity.
+func (s Severity) String() string {
+	switch s {
+	case ErrorSeverity:
+		return "error"
+	case CriticalSeverity:
+		return "critical"
+	default:
+		return "unknown"
+	}
+}
+
+type contextError struct {
+	error
+	ctx context.Context
+}
+
+func (e *contextError) Unwrap() error {
+	return e.error
+}
+
+func (e *contextError) Context() context.Context {
+	return e.ctx
+}
+
+// WithContext returns an error that wraps err with the context.
+// Notifiers get the request scope data (ex. request ID) from the context
+// with ContextFromError and include it in the notification.
+func WithContext(ctx context.Context, err error) error {
+	if err == nil || ctx == nil {
+		return err
+	}
+	return &contextError{error: err, ctx: ctx}
+}
+
+// errorMessage returns the message of the error, which can be nil like NewNopNotifier accepts.
+func errorMessage(err error) string {
+	if err == nil {
+		return "<nil>"
+	}
+	return err.Error()
+}
+
+// ContextFromError returns the context attached to the error by WithContext.
+// If it does not exists, returns context.Background().
+func ContextFromError(err error) context.Context {
+	var e interface{ Context() context.Context }
+	if errors.As(err, &e) {
+		return e.Context()
+	}
+	return context.Background()
+}
@@ -0,0 +1,80 @@
+package notice_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/takuoki/golib/appctx"
+	"github.com/takuoki/golib/notice"
+)
+
+func TestSeverity(t *testing.T) {
+	testcase := map[string]struct {
+		in   notice.Severity
+		want string
+	}{
+		"error":    {in: notice.ErrorSeverity, want: "error"},
+		"critical": {in: notice.CriticalSeverity, want: "critical"},
+		"unknown":  {in: 0, want: "unknown"},
+	}
+
+	for name, c := range testcase {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, c.want, c.in.String())
+		})
+	}
+}
+
+func TestContextFromError(t *testing.T) {
+	t.Run("exist", func(t *testing.T) {
+		base := errors.New("error")
+		ctx := appctx.WithRequestID(context.Background(), "req-1")
+		err := fmt.Errorf("wrapped: %w", notice.WithContext(ctx, base))
+		assert.Equal(t, "wrapped: error", err.Error())
+		assert.ErrorIs(t, err, base)
+		assert.Equal(t, "req-1", appctx.RequestID(notice.ContextFromError(err)))
+	})
+	t.Run("not-exist", func(t *testing.T) {
+		assert.Equal(t, context.Background(), notice.ContextFromError(errors.New("error")))
+	})
+	t.Run("nil", func(t *testing.T) {
+		assert.Nil(t, notice.WithContext(context.Background(), nil))
+	})
+}
+
+type errorNotifier struct {
+	errs []error
+}
+
+func (n *errorNotifier) Error(err error) error {
+	n.errs = append(n.errs, err)
+	return nil
+}
+
+func (n *errorNotifier) Critical(err error) error {
+	n.errs = append(n.errs, err)
+	return nil
+}
+
+func TestNewContextNotifier(t *testing.T) {
+	t.Run("adapter", func(t *testing.T) {
+		n := &errorNotifier{}
+		cn := notice.NewContextNotifier(n)
+
+		ctx := appctx.WithRequestID(context.Background(), "req-1")
+		assert.Nil(t, cn.ErrorContext(ctx, errors.New("error")))
+		assert.Nil(t, cn.CriticalContext(ctx, errors.New("critical")))
+		if assert.Len(t, n.errs, 2) {
+			assert.Equal(t, "error", n.errs[0].Error())
+			assert.Equal(t, "req-1", appctx.RequestID(notice.ContextFromError(n.errs[0])))
+			assert.Equal(t, "critical", n.errs[1].Error())
+		}
+	})
+	t.Run("as-is", func(t *testing.T) {
+		n := notice.NewNopNotifier()
+		assert.Equal(t, n, notice.NewContextNotifier(n))
+	})
+}
@@ -0,0 +1,130 @@
+// Package noticetest provides a notifier for testing code that uses notice.
+package noticetest
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/takuoki/golib/notice"
+)
+
+// Call is a record of a notification.
+type Call struct {
+	Severity notice.Severity
+	Err      error
+	// Ctx is the context passed to ErrorContext or CriticalContext,
+	// or the context attached to the error for Error and Critical.
+	Ctx context.Context
+}
+
+// Recorder is a notifier that records notifications instead of sending them.
+// It implements notice.Notifier and notice.ContextNotifier, and is safe for concurrent use.
+// The zero value is ready to use.
+type Recorder struct {
+	mu      sync.Mutex
+	calls   []Call
+	err     error
+	changed chan struct{}
+}
+
+// NewRecorder returns a new recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{changed: make(chan struct{})}
+}
+
+// SetError sets the error returned by the notification methods to simulate failures.
+// Notifications are recorded even if the error is set.
+func (r *Recorder) SetError(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.err = err
+}
+
+// Error records the error as an error.
+func (r *Recorder) Error(err error) error {
+	return r.record(notice.ContextFromError(err), notice.ErrorSeverity, err)
+}
+
+// Critical records the error as a critical error.
+func (r *Recorder) Critical(err error) error {
+	return r.record(notice.ContextFromError(err), notice.CriticalSeverity, err)
+}
+
+// ErrorContext records the error as an error with the context.
+func (r *Recorder) ErrorContext(ctx context.Context, err error) error {
+	return r.record(ctx, notice.ErrorSeverity, err)
+}
+
+// CriticalContext records the error as a critical error with the context.
+func (r *Recorder) CriticalContext(ctx context.Context, err error) error {
+	return r.record(ctx, notice.CriticalSeverity, err)
+}
+
+func (r *Recorder) record(ctx context.Context, s notice.Severity, err error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, Call{Severity: s, Err: err, Ctx: ctx})
+	if r.changed != nil {
+		close(r.changed)
+	}
+	r.changed = make(chan struct{})
+	return r.err
+}
+
+// Calls returns all recorded notifications in order.
+func (r *Recorder) Calls() []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Call(nil), r.calls...)
+}
+
+// CallsOf returns the recorded notifications of the severity in order.
+func (r *Recorder) CallsOf(s notice.Severity) []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	calls := []Call{}
+	for _, c := range r.calls {
+		if c.Severity == s {
+			calls = append(calls, c)
+		}
+	}
+	return calls
+}
+
+// WaitFor waits until a notification of the severity is recorded, and returns the first one.
+// If it has already been recorded, it returns immediately.
+// If the timeout passes, it returns false.
+// It is useful for code that notifies asynchronously.
+func (r *Recorder) WaitFor(s notice.Severity, timeout time.Duration) (Call, bool) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	for {
+		r.mu.Lock()
+		for _, c := range r.calls {
+			if c.Severity == s {
+				r.mu.Unlock()
+				return c, true
+			}
+		}
+		if r.changed == nil {
+			r.changed = make(chan struct{})
+		}
+		changed := r.changed
+		r.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-timer.C:
+			return Call{}, false
+		}
+	}
+}
+
+// Reset removes all recorded notifications and the error set by SetError.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = nil
+	r.err = nil
+}
@@ -0,0 +1,78 @@
+package noticetest_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/takuoki/golib/appctx"
+	"github.com/takuoki/golib/notice"
+	"github.com/takuoki/golib/notice/noticetest"
+)
+
+func TestRecorder(t *testing.T) {
+	t.Run("record", func(t *testing.T) {
+		r := noticetest.NewRecorder()
+		ctx := appctx.WithRequestID(context.Background(), "req-1")
+
+		assert.Nil(t, r.Error(notice.WithContext(ctx, errors.New("error"))))
+		assert.Nil(t, r.CriticalContext(ctx, errors.New("critical")))
+
+		calls := r.Calls()
+		if assert.Len(t, calls, 2) {
+			assert.Equal(t, notice.ErrorSeverity, calls[0].Severity)
+			assert.Equal(t, "error", calls[0].Err.Error())
+			assert.Equal(t, "req-1", appctx.RequestID(calls[0].Ctx))
+			assert.Equal(t, notice.CriticalSeverity, calls[1].Severity)
+			assert.Equal(t, "critical", calls[1].Err.Error())
+			assert.Equal(t, "req-1", appctx.RequestID(calls[1].Ctx))
+		}
+		assert.Len(t, r.CallsOf(notice.CriticalSeverity), 1)
+
+		r.Reset()
+		assert.Empty(t, r.Calls())
+	})
+	t.Run("set-error", func(t *testing.T) {
+		r := noticetest.NewRecorder()
+		r.SetError(errors.New("unavailable"))
+
+		assert.EqualError(t, r.Critical(errors.New("critical")), "unavailable")
+		assert.Len(t, r.Calls(), 1)
+
+		r.Reset()
+		assert.Nil(t, r.Critical(errors.New("critical")))
+	})
+	t.Run("wait-for", func(t *testing.T) {
+		r := noticetest.NewRecorder()
+
+		wg := sync.WaitGroup{}
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_ = r.Error(errors.New("error"))
+			}()
+		}
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			_ = r.Critical(errors.New("critical"))
+		}()
+
+		c, ok := r.WaitFor(notice.CriticalSeverity, time.Second)
+		if assert.True(t, ok) {
+			assert.Equal(t, "critical", c.Err.Error())
+		}
+		wg.Wait()
+		assert.Len(t, r.CallsOf(notice.ErrorSeverity), 10)
+	})
+	t.Run("wait-for-timeout", func(t *testing.T) {
+		r := noticetest.NewRecorder()
+		_ = r.Error(errors.New("error"))
+
+		_, ok := r.WaitFor(notice.CriticalSeverity, 10*time.Millisecond)
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,161 @@
+package notice
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/takuoki/golib/appctx"
+)
+
+const defaultSlackMention = "<!channel>"
+
+type slackNotifier struct {
+	webhookURL string
+	mention    string
+	sender     httpSender
+}
+
+// SlackOption is an option for NewSlackNotifier.
+type SlackOption func(*slackNotifier)
+
+// SlackTimeout sets the timeout for each request to Slack.
+// The default is 5 seconds. Specify 0 for no timeout.
+func SlackTimeout(d time.Duration) SlackOption {
+	return func(n *slackNotifier) {
+		n.sender.timeout = d
+	}
+}
+
+// SlackRetry sets the number of retries and the interval between them
+// when the request to Slack fails. The default is 2 retries at 1 second intervals.
+// Specify 0 for no retry.
+func SlackRetry(retry int, interval time.Duration) SlackOption {
+	return func(n *slackNotifier) {
+		n.sender.retry = retry
+		n.sender.retryInterval = interval
+	}
+}
+
+// SlackMention sets the mention added to Critical notifications (ex. "<@U012AB3CD>").
+// The default is "<!channel>". Specify an empty string to disable the mention.
+func SlackMention(mention string) SlackOption {
+	return func(n *slackNotifier) {
+		n.mention = mention
+	}
+}
+
+// SlackHTTPClient sets the HTTP client used for requests to Slack.
+// The default is http.DefaultClient.
+func SlackHTTPClient(c *http.Client) SlackOption {
+	return func(n *slackNotifier) {
+		n.sender.client = c
+	}
+}
+
+// NewSlackNotifier returns a notifier that posts a message to Slack incoming webhook.
+// Error is posted in yellow, and Critical is posted in red with a mention.
+// The request ID and user ID held in the context of ErrorContext and CriticalContext,
+// or in the context attached to the error by WithContext, are included in the message.
+func NewSlackNotifier(webhookURL string, opts ...SlackOption) (Notifier, error) {
+	if webhookURL == "" {
+		return nil, errors.New("slack webhook URL is empty")
+	}
+	if _, err := url.ParseRequestURI(webhookURL); err != nil {
+		return nil, fmt.Errorf("invalid slack webhook URL: %w", err)
+	}
+	n := &slackNotifier{
+		webhookURL: webhookURL,
+		mention:    defaultSlackMention,
+		sender:     newHTTPSender(),
+	}
+	for _, opt := range opts {
+		opt(n)
+	}
+	if err := n.sender.validate(); err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+// Error posts the error to Slack as an error.
+func (n *slackNotifier) Error(err error) error {
+	return n.notify(ContextFromError(err), ErrorSeverity, err)
+}
+
+// ErrorContext posts the error to Slack as an error
+// with the request scope data held in the context.
+func (n *slackNotifier) ErrorContext(ctx context.Context, err error) error {
+	return n.notify(ctx, ErrorSeverity, err)
+}
+
+// Critical posts the error to Slack as a critical error.
+func (n *slackNotifier) Critical(err error) error {
+	return n.notify(ContextFromError(err), CriticalSeverity, err)
+}
+
+// CriticalContext posts the error to Slack as a critical error
+// with the request scope data held in the context.
+func (n *slackNotifier) CriticalContext(ctx context.Context, err error) error {
+	return n.notify(ctx, CriticalSeverity, err)
+}
+
+func (n *slackNotifier) notify(ctx context.Context, s Severity, err error) error {
+	body, err := json.Marshal(n.message(ctx, s, err))
+	if err != nil {
+		return fmt.Errorf("failed to marshal slack message: %w", err)
+	}
+	header := http.Header{"Content-Type": []string{"application/json"}}
+	if err := n.sender.send(context.WithoutCancel(ctx), n.webhookURL, header, body); err != nil {
+		return fmt.Errorf("failed to notify to slack: %w", err)
+	}
+	return nil
+}
+
+type slackMessage struct {
+	Text        string            `json:"text"`
+	Attachments []slackAttachment `json:"attachments"`
+}
+
+type slackAttachment struct {
+	Color  string       `json:"color"`
+	Title  string       `json:"title"`
+	Text   string       `json:"text"`
+	Fields []slackField `json:"fields,omitempty"`
+	Ts     int64        `json:"ts"`
+}
+
+type slackField struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+	Short bool   `json:"short"`
+}
+
+func (n *slackNotifier) message(ctx context.Context, s Severity, err error) slackMessage {
+	text := "An error occurred."
+	attachment := slackAttachment{
+		Color: "warning",
+		Title: "Error",
+		Text:  errorMessage(err),
+		Ts:    time.Now().Unix(),
+	}
+	if s == CriticalSeverity {
+		text = "A critical error occurred."
+		if n.mention != "" {
+			text = n.mention + " " + text
+		}
+		attachment.Color = "danger"
+		attachment.Title = "Critical"
+	}
+	if requestID := appctx.RequestID(ctx); requestID != "" {
+		attachment.Fields = append(attachment.Fields, slackField{Title: "Request ID", Value: requestID, Short: true})
+	}
+	if userID := appctx.UserID(ctx); userID != "" {
+		attachment.Fields = append(attachment.Fields, slackField{Title: "User ID", Value: userID, Short: true})
+	}
+	return slackMessage{Text: text, Attachments: []slackAttachment{attachment}}
+}
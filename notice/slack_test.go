@@ -0,0 +1,188 @@
+package notice_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/takuoki/golib/appctx"
+	"github.com/takuoki/golib/notice"
+)
+
+type slackRequest struct {
+	Text        string `json:"text"`
+	Attachments []struct {
+		Color  string `json:"color"`
+		Title  string `json:"title"`
+		Text   string `json:"text"`
+		Fields []struct {
+			Title string `json:"title"`
+			Value string `json:"value"`
+		} `json:"fields"`
+	} `json:"attachments"`
+}
+
+func newSlackServer(t *testing.T, statuses ...int) (*httptest.Server, *[]slackRequest) {
+	reqs := []slackRequest{}
+	var cnt int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		i := int(atomic.AddInt32(&cnt, 1)) - 1
+		b, _ := io.ReadAll(r.Body)
+		var req slackRequest
+		if err := json.Unmarshal(b, &req); err != nil {
+			t.Errorf("failed to unmarshal request body: %v", err)
+		}
+		reqs = append(reqs, req)
+		if i < len(statuses) {
+			w.WriteHeader(statuses[i])
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &reqs
+}
+
+func TestSlackNotifier(t *testing.T) {
+	t.Run("error", func(t *testing.T) {
+		srv, reqs := newSlackServer(t)
+		n, err := notice.NewSlackNotifier(srv.URL)
+		if !assert.Nil(t, err) {
+			return
+		}
+
+		assert.Nil(t, n.Error(errors.New("error message")))
+		if assert.Len(t, *reqs, 1) {
+			req := (*reqs)[0]
+			assert.Equal(t, "An error occurred.", req.Text)
+			if assert.Len(t, req.Attachments, 1) {
+				assert.Equal(t, "warning", req.Attachments[0].Color)
+				assert.Equal(t, "Error", req.Attachments[0].Title)
+				assert.Equal(t, "error message", req.Attachments[0].Text)
+				assert.Empty(t, req.Attachments[0].Fields)
+			}
+		}
+	})
+	t.Run("critical", func(t *testing.T) {
+		srv, reqs := newSlackServer(t)
+		n, err := notice.NewSlackNotifier(srv.URL, notice.SlackMention("<@U012AB3CD>"))
+		if !assert.Nil(t, err) {
+			return
+		}
+
+		ctx := appctx.WithRequestID(context.Background(), "req-1")
+		assert.Nil(t, n.Critical(notice.WithContext(ctx, errors.New("critical message"))))
+		if assert.Len(t, *reqs, 1) {
+			req := (*reqs)[0]
+			assert.Equal(t, "<@U012AB3CD> A critical error occurred.", req.Text)
+			if assert.Len(t, req.Attachments, 1) {
+				assert.Equal(t, "danger", req.Attachments[0].Color)
+				assert.Equal(t, "Critical", req.Attachments[0].Title)
+				assert.Equal(t, "critical message", req.Attachments[0].Text)
+				if assert.Len(t, req.Attachments[0].Fields, 1) {
+					assert.Equal(t, "Request ID", req.Attachments[0].Fields[0].Title)
+					assert.Equal(t, "req-1", req.Attachments[0].Fields[0].Value)
+				}
+			}
+		}
+	})
+	t.Run("nil-error", func(t *testing.T) {
+		srv, reqs := newSlackServer(t)
+		n, err := notice.NewSlackNotifier(srv.URL)
+		if !assert.Nil(t, err) {
+			return
+		}
+
+		assert.Nil(t, n.Error(nil))
+		if assert.Len(t, *reqs, 1) && assert.Len(t, (*reqs)[0].Attachments, 1) {
+			assert.Equal(t, "<nil>", (*reqs)[0].Attachments[0].Text)
+		}
+	})
+	t.Run("retry", func(t *testing.T) {
+		srv, reqs := newSlackServer(t, http.StatusInternalServerError, http.StatusTooManyRequests)
+		n, err := notice.NewSlackNotifier(srv.URL, notice.SlackRetry(2, time.Millisecond))
+		if !assert.Nil(t, err) {
+			return
+		}
+
+		assert.Nil(t, n.Error(errors.New("error message")))
+		assert.Len(t, *reqs, 3)
+	})
+	t.Run("retry-over", func(t *testing.T) {
+		srv, reqs := newSlackServer(t, http.StatusInternalServerError, http.StatusInternalServerError)
+		n, err := notice.NewSlackNotifier(srv.URL, notice.SlackRetry(1, time.Millisecond))
+		if !assert.Nil(t, err) {
+			return
+		}
+
+		err = n.Error(errors.New("error message"))
+		if assert.NotNil(t, err) {
+			assert.Equal(t, "failed to notify to slack: unexpected status 500: ", err.Error())
+		}
+		assert.Len(t, *reqs, 2)
+	})
+	t.Run("not-retryable", func(t *testing.T) {
+		srv, reqs := newSlackServer(t, http.StatusBadRequest)
+		n, err := notice.NewSlackNotifier(srv.URL, notice.SlackRetry(2, time.Millisecond))
+		if !assert.Nil(t, err) {
+			return
+		}
+
+		assert.NotNil(t, n.Error(errors.New("error message")))
+		assert.Len(t, *reqs, 1)
+	})
+	t.Run("timeout", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(100 * time.Millisecond)
+		}))
+		defer srv.Close()
+		n, err := notice.NewSlackNotifier(srv.URL, notice.SlackTimeout(10*time.Millisecond), notice.SlackRetry(0, 0))
+		if !assert.Nil(t, err) {
+			return
+		}
+
+		err = n.Error(errors.New("error message"))
+		if assert.NotNil(t, err) {
+			assert.ErrorIs(t, err, context.DeadlineExceeded)
+		}
+	})
+}
+
+func TestNewSlackNotifierError(t *testing.T) {
+	_, err := notice.NewSlackNotifier("")
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "slack webhook URL is empty", err.Error())
+	}
+	_, err = notice.NewSlackNotifier("invalid")
+	assert.NotNil(t, err)
+	_, err = notice.NewSlackNotifier("http://localhost", notice.SlackRetry(-1, time.Second))
+	assert.EqualError(t, err, "invalid retry: -1")
+	_, err = notice.NewSlackNotifier("http://localhost", notice.SlackTimeout(-time.Second))
+	assert.EqualError(t, err, "invalid timeout: -1s")
+}
+
+func TestSlackNotifierContext(t *testing.T) {
+	srv, reqs := newSlackServer(t)
+	n, err := notice.NewSlackNotifier(srv.URL)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	ctx := appctx.WithRequestID(context.Background(), "req-1")
+	ctx = appctx.WithUserID(ctx, "user-1")
+	assert.Nil(t, notice.NewContextNotifier(n).CriticalContext(ctx, errors.New("critical message")))
+	if assert.Len(t, *reqs, 1) && assert.Len(t, (*reqs)[0].Attachments, 1) {
+		fields := (*reqs)[0].Attachments[0].Fields
+		if assert.Len(t, fields, 2) {
+			assert.Equal(t, "req-1", fields[0].Value)
+			assert.Equal(t, "user-1", fields[1].Value)
+		}
+	}
+}
@@ -0,0 +1,247 @@
+package notice
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Default templates for NewSMTPNotifier.
+const (
+	DefaultSMTPErrorSubjectTemplate    = `[ERROR] {{.Message}}`
+	DefaultSMTPCriticalSubjectTemplate = `[CRITICAL] {{.Message}}`
+	DefaultSMTPBodyTemplate            = `Severity: {{.Severity}}
+Message: {{.Message}}
+Request ID: {{.RequestID}}
+User ID: {{.UserID}}
+Time: {{.Time.Format "2006-01-02T15:04:05Z07:00"}}
+`
+)
+
+type smtpNotifier struct {
+	addr      string
+	host      string
+	from      string
+	to        []string
+	auth      smtp.Auth
+	timeout   time.Duration
+	tlsConfig *tls.Config
+
+	subjectTexts map[Severity]string
+	bodyTexts    map[Severity]string
+	subjects     map[Severity]*template.Template
+	bodies       map[Severity]*template.Template
+}
+
+// SMTPOption is an option for NewSMTPNotifier.
+type SMTPOption func(*smtpNotifier)
+
+// SMTPAuth sets the authentication mechanism (ex. smtp.PlainAuth).
+// The default is no authentication.
+func SMTPAuth(auth smtp.Auth) SMTPOption {
+	return func(n *smtpNotifier) {
+		n.auth = auth
+	}
+}
+
+// SMTPTimeout sets the timeout for the whole SMTP session.
+// The default is 5 seconds.
+func SMTPTimeout(d time.Duration) SMTPOption {
+	return func(n *smtpNotifier) {
+		n.timeout = d
+	}
+}
+
+// SMTPTLSConfig sets the TLS configuration used for STARTTLS.
+// STARTTLS is used when the server supports it.
+func SMTPTLSConfig(c *tls.Config) SMTPOption {
+	return func(n *smtpNotifier) {
+		n.tlsConfig = c
+	}
+}
+
+// SMTPSubjectTemplate sets the text/template of the subject for the severity.
+// The template is executed with Notification.
+func SMTPSubjectTemplate(s Severity, tmpl string) SMTPOption {
+	return func(n *smtpNotifier) {
+		n.subjectTexts[s] = tmpl
+	}
+}
+
+// SMTPBodyTemplate sets the text/template of the body for the severity.
+// The template is executed with Notification.
+func SMTPBodyTemplate(s Severity, tmpl string) SMTPOption {
+	return func(n *smtpNotifier) {
+		n.bodyTexts[s] = tmpl
+	}
+}
+
+// NewSMTPNotifier returns a notifier that sends an email via the SMTP server at `addr` (host:port).
+// If any template cannot be parsed, an error will be returned.
+func NewSMTPNotifier(addr, from string, to []string, opts ...SMTPOption) (Notifier, error) {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SMTP server address: %w", err)
+	}
+	if from == "" {
+		return nil, errors.New("sender address is empty")
+	}
+	if len(to) == 0 {
+		return nil, errors.New("recipient addresses are empty")
+	}
+	n := &smtpNotifier{
+		addr:    addr,
+		host:    host,
+		from:    from,
+		to:      to,
+		timeout: defaultTimeout,
+		subjectTexts: map[Severity]string{
+			ErrorSeverity:    DefaultSMTPErrorSubjectTemplate,
+			CriticalSeverity: DefaultSMTPCriticalSubjectTemplate,
+		},
+		bodyTexts: map[Severity]string{
+			ErrorSeverity:    DefaultSMTPBodyTemplate,
+			CriticalSeverity: DefaultSMTPBodyTemplate,
+		},
+		subjects: map[Severity]*template.Template{},
+		bodies:   map[Severity]*template.Template{},
+	}
+	for _, opt := range opts {
+		opt(n)
+	}
+
+	for _, s := range []Severity{ErrorSeverity, CriticalSeverity} {
+		if n.subjects[s], err = parseTemplate(s.String()+" subject", n.subjectTexts[s]); err != nil {
+			return nil, err
+		}
+		if n.bodies[s], err = parseTemplate(s.String()+" body", n.bodyTexts[s]); err != nil {
+			return nil, err
+		}
+	}
+
+	return n, nil
+}
+
+// Error sends the error by email as an error.
+func (n *smtpNotifier) Error(err error) error {
+	return n.notify(ContextFromError(err), ErrorSeverity, err)
+}
+
+// ErrorContext sends the error by email as an error
+// with the request scope data held in the context.
+func (n *smtpNotifier) ErrorContext(ctx context.Context, err error) error {
+	return n.notify(ctx, ErrorSeverity, err)
+}
+
+// Critical sends the error by email as a critical error.
+func (n *smtpNotifier) Critical(err error) error {
+	return n.notify(ContextFromError(err), CriticalSeverity, err)
+}
+
+// CriticalContext sends the error by email as a critical error
+// with the request scope data held in the context.
+func (n *smtpNotifier) CriticalContext(ctx context.Context, err error) error {
+	return n.notify(ctx, CriticalSeverity, err)
+}
+
+func (n *smtpNotifier) notify(ctx context.Context, s Severity, err error) error {
+	msg, err := n.message(newNotification(ctx, s, err))
+	if err != nil {
+		return fmt.Errorf("failed to notify by email: %w", err)
+	}
+	if err := n.send(msg); err != nil {
+		return fmt.Errorf("failed to notify by email: %w", err)
+	}
+	return nil
+}
+
+func (n *smtpNotifier) message(data Notification) ([]byte, error) {
+	subject, err := executeTemplate(n.subjects[data.Severity], data)
+	if err != nil {
+		return nil, err
+	}
+	body, err := executeTemplate(n.bodies[data.Severity], data)
+	if err != nil {
+		return nil, err
+	}
+
+	// Header values must not contain line breaks.
+	sub := strings.Join(strings.Fields(string(subject)), " ")
+
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "From: %s\r\n", n.from)
+	fmt.Fprintf(buf, "To: %s\r\n", strings.Join(n.to, ", "))
+	fmt.Fprintf(buf, "Subject: %s\r\n", mime.QEncoding.Encode("UTF-8", sub))
+	fmt.Fprintf(buf, "Date: %s\r\n", data.Time.Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	buf.WriteString("\r\n")
+	buf.WriteString(strings.ReplaceAll(strings.ReplaceAll(string(body), "\r\n", "\n"), "\n", "\r\n"))
+	return buf.Bytes(), nil
+}
+
+// send sends the message in the same way as smtp.SendMail, but with the timeout.
+func (n *smtpNotifier) send(msg []byte) error {
+	conn, err := net.DialTimeout("tcp", n.addr, n.timeout)
+	if err != nil {
+		return err
+	}
+	if n.timeout > 0 {
+		if err := conn.SetDeadline(time.Now().Add(n.timeout)); err != nil {
+			conn.Close() //nolint:errcheck
+			return err
+		}
+	}
+
+	c, err := smtp.NewClient(conn, n.host)
+	if err != nil {
+		conn.Close() //nolint:errcheck
+		return err
+	}
+	defer c.Close() //nolint:errcheck
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		config := n.tlsConfig
+		if config == nil {
+			config = &tls.Config{ServerName: n.host}
+		}
+		if err := c.StartTLS(config); err != nil {
+			return err
+		}
+	}
+	if n.auth != nil {
+		if ok, _ := c.Extension("AUTH"); !ok {
+			return errors.New("SMTP server doesn't support AUTH")
+		}
+		if err := c.Auth(n.auth); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(n.from); err != nil {
+		return err
+	}
+	for _, addr := range n.to {
+		if err := c.Rcpt(addr); err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
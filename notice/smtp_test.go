@@ -0,0 +1,176 @@
+package notice_test
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/takuoki/golib/appctx"
+	"github.com/takuoki/golib/notice"
+)
+
+type mail struct {
+	from string
+	to   []string
+	data string
+}
+
+// startSMTPServer starts a minimal SMTP server that accepts any mail.
+func startSMTPServer(t *testing.T) (string, <-chan mail) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	mails := make(chan mail, 10)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go serveSMTP(conn, mails)
+		}
+	}()
+	return ln.Addr().String(), mails
+}
+
+func serveSMTP(conn net.Conn, mails chan<- mail) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(s string) { _, _ = conn.Write([]byte(s + "\r\n")) }
+
+	m := mail{}
+	reply("220 localhost ESMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		cmd := strings.ToUpper(line)
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			reply("250 localhost")
+		case strings.HasPrefix(cmd, "MAIL FROM:"):
+			m.from = strings.Trim(line[len("MAIL FROM:"):], "<>")
+			reply("250 OK")
+		case strings.HasPrefix(cmd, "RCPT TO:"):
+			m.to = append(m.to, strings.Trim(line[len("RCPT TO:"):], "<>"))
+			reply("250 OK")
+		case cmd == "DATA":
+			reply("354 Start mail input")
+			data := &strings.Builder{}
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				data.WriteString(l)
+			}
+			m.data = data.String()
+			mails <- m
+			reply("250 OK")
+		case cmd == "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("502 Command not implemented")
+		}
+	}
+}
+
+func TestSMTPNotifier(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		addr, mails := startSMTPServer(t)
+		n, err := notice.NewSMTPNotifier(addr, "alert@example.com", []string{"a@example.com", "b@example.com"})
+		if !assert.Nil(t, err) {
+			return
+		}
+
+		ctx := appctx.WithRequestID(context.Background(), "req-1")
+		if !assert.Nil(t, n.Critical(notice.WithContext(ctx, errors.New("critical message")))) {
+			return
+		}
+
+		m := <-mails
+		assert.Equal(t, "alert@example.com", m.from)
+		assert.Equal(t, []string{"a@example.com", "b@example.com"}, m.to)
+		assert.Contains(t, m.data, "From: alert@example.com\r\n")
+		assert.Contains(t, m.data, "To: a@example.com, b@example.com\r\n")
+		assert.Contains(t, m.data, "Subject: [CRITICAL] critical message\r\n")
+		assert.Contains(t, m.data, "\r\n\r\nSeverity: critical\r\nMessage: critical message\r\nRequest ID: req-1\r\nUser ID: \r\n")
+	})
+	t.Run("template", func(t *testing.T) {
+		addr, mails := startSMTPServer(t)
+		n, err := notice.NewSMTPNotifier(
+			addr, "alert@example.com", []string{"a@example.com"},
+			notice.SMTPSubjectTemplate(notice.ErrorSeverity, "エラー: {{.Message}}"),
+			notice.SMTPBodyTemplate(notice.ErrorSeverity, "{{.Message}}\n"),
+		)
+		if !assert.Nil(t, err) {
+			return
+		}
+
+		if !assert.Nil(t, n.Error(errors.New("error\nmessage"))) {
+			return
+		}
+
+		m := <-mails
+		assert.Contains(t, m.data, "Subject: =?UTF-8?q?=E3=82=A8=E3=83=A9=E3=83=BC:_error_message?=\r\n")
+		assert.True(t, strings.HasSuffix(m.data, "\r\n\r\nerror\r\nmessage\r\n"), "body doesn't match: %q", m.data)
+	})
+	t.Run("connection-error", func(t *testing.T) {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("failed to listen: %v", err)
+		}
+		addr := ln.Addr().String()
+		ln.Close()
+
+		n, err := notice.NewSMTPNotifier(addr, "alert@example.com", []string{"a@example.com"})
+		if !assert.Nil(t, err) {
+			return
+		}
+		err = n.Error(errors.New("error message"))
+		if assert.NotNil(t, err) {
+			assert.Regexp(t, "^failed to notify by email: ", err.Error())
+		}
+	})
+}
+
+func TestNewSMTPNotifierError(t *testing.T) {
+	testcase := map[string]struct {
+		addr    string
+		from    string
+		to      []string
+		opts    []notice.SMTPOption
+		wantErr string
+	}{
+		"invalid-addr": {addr: "localhost", from: "a@example.com", to: []string{"b@example.com"}, wantErr: "^invalid SMTP server address: "},
+		"empty-from":   {addr: "localhost:25", to: []string{"b@example.com"}, wantErr: "^sender address is empty$"},
+		"empty-to":     {addr: "localhost:25", from: "a@example.com", wantErr: "^recipient addresses are empty$"},
+		"invalid-template": {
+			addr: "localhost:25", from: "a@example.com", to: []string{"b@example.com"},
+			opts:    []notice.SMTPOption{notice.SMTPBodyTemplate(notice.CriticalSeverity, "{{.Message")},
+			wantErr: "^failed to parse critical body template: ",
+		},
+	}
+
+	for name, c := range testcase {
+		t.Run(name, func(t *testing.T) {
+			_, err := notice.NewSMTPNotifier(c.addr, c.from, c.to, c.opts...)
+			if assert.NotNil(t, err) {
+				assert.Regexp(t, c.wantErr, err.Error())
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package notice
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"text/template"
+	"time"
+
+	"github.com/takuoki/golib/appctx"
+)
+
+// Notification is the data passed to the templates of notifiers.
+type Notification struct {
+	Severity  Severity
+	Message   string
+	RequestID string
+	UserID    string
+	Time      time.Time
+}
+
+func newNotification(ctx context.Context, s Severity, err error) Notification {
+	return Notification{
+		Severity:  s,
+		Message:   errorMessage(err),
+		RequestID: appctx.RequestID(ctx),
+		UserID:    appctx.UserID(ctx),
+		Time:      time.Now(),
+	}
+}
+
+var templateFuncs = template.FuncMap{
+	// json returns the value encoded as JSON (ex. a quoted and escaped string).
+	"json": func(v interface{}) (string, error) {
+		b, err := json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+	},
+}
+
+func parseTemplate(name, text string) (*template.Template, error) {
+	tmpl, err := template.New(name).Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s template: %w", name, err)
+	}
+	return tmpl, nil
+}
+
+func executeTemplate(tmpl *template.Template, n Notification) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	if err := tmpl.Execute(buf, n); err != nil {
+		return nil, fmt.Errorf("failed to execute %s template: %w", tmpl.Name(), err)
+	}
+	return buf.Bytes(), nil
+}
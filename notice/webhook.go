@@ -0,0 +1,139 @@
+package notice
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"text/template"
+	"time"
+)
+
+// DefaultWebhookTemplate is the default template of the request body for NewWebhookNotifier.
+const DefaultWebhookTemplate = `{"severity":{{json .Severity.String}},"message":{{json .Message}},"request_id":{{json .RequestID}},"user_id":{{json .UserID}},"time":{{json .Time}}}`
+
+type webhookNotifier struct {
+	url          string
+	header       http.Header
+	templateText string
+	template     *template.Template
+	sender       httpSender
+}
+
+// WebhookOption is an option for NewWebhookNotifier.
+type WebhookOption func(*webhookNotifier)
+
+// WebhookTemplate sets the text/template of the JSON request body.
+// The template is executed with Notification, and the function `json`
+// that encodes a value as JSON is available (ex. {"text":{{json .Message}}}).
+// The default is DefaultWebhookTemplate.
+func WebhookTemplate(tmpl string) WebhookOption {
+	return func(n *webhookNotifier) {
+		n.templateText = tmpl
+	}
+}
+
+// WebhookHeader adds the header to each request (ex. Authorization).
+func WebhookHeader(key, value string) WebhookOption {
+	return func(n *webhookNotifier) {
+		n.header.Add(key, value)
+	}
+}
+
+// WebhookTimeout sets the timeout for each request.
+// The default is 5 seconds. Specify 0 for no timeout.
+func WebhookTimeout(d time.Duration) WebhookOption {
+	return func(n *webhookNotifier) {
+		n.sender.timeout = d
+	}
+}
+
+// WebhookRetry sets the number of retries and the interval between them
+// when the request fails. The default is 2 retries at 1 second intervals.
+// Specify 0 for no retry.
+func WebhookRetry(retry int, interval time.Duration) WebhookOption {
+	return func(n *webhookNotifier) {
+		n.sender.retry = retry
+		n.sender.retryInterval = interval
+	}
+}
+
+// WebhookHTTPClient sets the HTTP client used for requests.
+// The default is http.DefaultClient.
+func WebhookHTTPClient(c *http.Client) WebhookOption {
+	return func(n *webhookNotifier) {
+		n.sender.client = c
+	}
+}
+
+// NewWebhookNotifier returns a notifier that posts a JSON body to the URL.
+// If the template cannot be parsed, an error will be returned.
+func NewWebhookNotifier(webhookURL string, opts ...WebhookOption) (Notifier, error) {
+	if webhookURL == "" {
+		return nil, errors.New("webhook URL is empty")
+	}
+	if _, err := url.ParseRequestURI(webhookURL); err != nil {
+		return nil, fmt.Errorf("invalid webhook URL: %w", err)
+	}
+	n := &webhookNotifier{
+		url:          webhookURL,
+		header:       http.Header{},
+		templateText: DefaultWebhookTemplate,
+		sender:       newHTTPSender(),
+	}
+	for _, opt := range opts {
+		opt(n)
+	}
+	if err := n.sender.validate(); err != nil {
+		return nil, err
+	}
+	if n.header.Get("Content-Type") == "" {
+		n.header.Set("Content-Type", "application/json")
+	}
+
+	tmpl, err := parseTemplate("webhook", n.templateText)
+	if err != nil {
+		return nil, err
+	}
+	n.template = tmpl
+
+	return n, nil
+}
+
+// Error posts the error to the webhook as an error.
+func (n *webhookNotifier) Error(err error) error {
+	return n.notify(ContextFromError(err), ErrorSeverity, err)
+}
+
+// ErrorContext posts the error to the webhook as an error
+// with the request scope data held in the context.
+func (n *webhookNotifier) ErrorContext(ctx context.Context, err error) error {
+	return n.notify(ctx, ErrorSeverity, err)
+}
+
+// Critical posts the error to the webhook as a critical error.
+func (n *webhookNotifier) Critical(err error) error {
+	return n.notify(ContextFromError(err), CriticalSeverity, err)
+}
+
+// CriticalContext posts the error to the webhook as a critical error
+// with the request scope data held in the context.
+func (n *webhookNotifier) CriticalContext(ctx context.Context, err error) error {
+	return n.notify(ctx, CriticalSeverity, err)
+}
+
+func (n *webhookNotifier) notify(ctx context.Context, s Severity, err error) error {
+	body, err := executeTemplate(n.template, newNotification(ctx, s, err))
+	if err != nil {
+		return fmt.Errorf("failed to notify to webhook: %w", err)
+	}
+	if !json.Valid(body) {
+		return fmt.Errorf("failed to notify to webhook: request body is not valid JSON: %s", body)
+	}
+	if err := n.sender.send(context.WithoutCancel(ctx), n.url, n.header, body); err != nil {
+		return fmt.Errorf("failed to notify to webhook: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package notice_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/takuoki/golib/appctx"
+	"github.com/takuoki/golib/notice"
+)
+
+type webhookRequest struct {
+	header http.Header
+	body   string
+}
+
+func newWebhookServer(t *testing.T) (*httptest.Server, *[]webhookRequest) {
+	reqs := []webhookRequest{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		reqs = append(reqs, webhookRequest{header: r.Header, body: string(b)})
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &reqs
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		srv, reqs := newWebhookServer(t)
+		n, err := notice.NewWebhookNotifier(srv.URL)
+		if !assert.Nil(t, err) {
+			return
+		}
+
+		ctx := appctx.WithRequestID(context.Background(), "req-1")
+		assert.Nil(t, n.Critical(notice.WithContext(ctx, errors.New(`"quoted" message`))))
+		if assert.Len(t, *reqs, 1) {
+			req := (*reqs)[0]
+			assert.Equal(t, "application/json", req.header.Get("Content-Type"))
+			assert.Regexp(t, `^{"severity":"critical","message":"\\"quoted\\" message","request_id":"req-1","user_id":"","time":"[^"]+"}$`, req.body)
+		}
+	})
+	t.Run("custom", func(t *testing.T) {
+		srv, reqs := newWebhookServer(t)
+		n, err := notice.NewWebhookNotifier(
+			srv.URL,
+			notice.WebhookTemplate(`{"summary":{{json .Message}},"level":"{{if eq .Severity.String "critical"}}P1{{else}}P3{{end}}"}`),
+			notice.WebhookHeader("Authorization", "Bearer token"),
+			notice.WebhookHeader("Content-Type", "application/vnd.alert+json"),
+		)
+		if !assert.Nil(t, err) {
+			return
+		}
+
+		assert.Nil(t, n.Error(errors.New("error message")))
+		if assert.Len(t, *reqs, 1) {
+			req := (*reqs)[0]
+			assert.Equal(t, "Bearer token", req.header.Get("Authorization"))
+			assert.Equal(t, "application/vnd.alert+json", req.header.Get("Content-Type"))
+			assert.Equal(t, `{"summary":"error message","level":"P3"}`, req.body)
+		}
+	})
+	t.Run("nil-error", func(t *testing.T) {
+		srv, reqs := newWebhookServer(t)
+		n, err := notice.NewWebhookNotifier(srv.URL, notice.WebhookTemplate(`{"message":{{json .Message}}}`))
+		if !assert.Nil(t, err) {
+			return
+		}
+
+		assert.Nil(t, n.Error(nil))
+		if assert.Len(t, *reqs, 1) {
+			assert.JSONEq(t, `{"message":"<nil>"}`, (*reqs)[0].body)
+		}
+	})
+	t.Run("invalid-json", func(t *testing.T) {
+		srv, reqs := newWebhookServer(t)
+		n, err := notice.NewWebhookNotifier(srv.URL, notice.WebhookTemplate(`{"message":"{{.Message}}"}`))
+		if !assert.Nil(t, err) {
+			return
+		}
+
+		err = n.Error(errors.New(`"quoted" message`))
+		if assert.NotNil(t, err) {
+			assert.Regexp(t, "^failed to notify to webhook: request body is not valid JSON: ", err.Error())
+		}
+		assert.Empty(t, *reqs)
+	})
+}
+
+func TestNewWebhookNotifierError(t *testing.T) {
+	t.Run("empty-url", func(t *testing.T) {
+		_, err := notice.NewWebhookNotifier("")
+		if assert.NotNil(t, err) {
+			assert.Equal(t, "webhook URL is empty", err.Error())
+		}
+	})
+	t.Run("negative-retry", func(t *testing.T) {
+		_, err := notice.NewWebhookNotifier("http://localhost", notice.WebhookRetry(-1, time.Second))
+		assert.EqualError(t, err, "invalid retry: -1")
+	})
+	t.Run("negative-timeout", func(t *testing.T) {
+		_, err := notice.NewWebhookNotifier("http://localhost", notice.WebhookTimeout(-time.Second))
+		assert.EqualError(t, err, "invalid timeout: -1s")
+	})
+	t.Run("invalid-template", func(t *testing.T) {
+		_, err := notice.NewWebhookNotifier("http://localhost", notice.WebhookTemplate("{{.Message"))
+		if assert.NotNil(t, err) {
+			assert.Regexp(t, "^failed to parse webhook template: ", err.Error())
+		}
+	})
+}
+
+func TestWebhookNotifierContext(t *testing.T) {
+	srv, reqs := newWebhookServer(t)
+	n, err := notice.NewWebhookNotifier(srv.URL)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	ctx := appctx.WithRequestID(context.Background(), "req-1")
+	ctx = appctx.WithUserID(ctx, "user-1")
+	assert.Nil(t, notice.NewContextNotifier(n).ErrorContext(ctx, errors.New("error message")))
+	if assert.Len(t, *reqs, 1) {
+		assert.Regexp(t, `^{"severity":"error","message":"error message","request_id":"req-1","user_id":"user-1","time":"[^"]+"}$`, (*reqs)[0].body)
+	}
+}